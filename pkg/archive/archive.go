@@ -0,0 +1,184 @@
+// Package archive parses the training instance archives exported by kypo.Client.ExportTrainingInstanceArchive
+// for offline analysis.
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+)
+
+// Archive is the content of a training instance archive.
+type Archive struct {
+	Instance Instance
+	// DefinitionContent is the exported training definition of the instance, which can be parsed by
+	// kypo.ParseLinearTrainingDefinition or kypo.ParseAdaptiveTrainingDefinition.
+	DefinitionContent string
+	// Runs are the training runs of the instance ordered by their id.
+	Runs []Run
+}
+
+type Instance struct {
+	Id          int64     `json:"id"`
+	Title       string    `json:"title"`
+	StartTime   time.Time `json:"start_time"`
+	EndTime     time.Time `json:"end_time"`
+	AccessToken string    `json:"access_token"`
+	PoolId      int64     `json:"pool_id"`
+}
+
+type Run struct {
+	Id               int64                 `json:"id"`
+	StartTime        time.Time             `json:"start_time"`
+	EndTime          time.Time             `json:"end_time"`
+	State            kypo.TrainingRunState `json:"state"`
+	ParticipantRefId int64                 `json:"participant_ref_id"`
+	SandboxId        string                `json:"sandbox_instance_ref_id"`
+	// Events are the training events of the run ordered by their timestamp.
+	Events []kypo.TrainingEvent `json:"-"`
+}
+
+var runIdPattern = regexp.MustCompile(`training_run-id(\d+)`)
+
+// Open reads the archive from the file given by name.
+func Open(name string) (*Archive, error) {
+	reader, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return parse(reader.File)
+}
+
+// Read reads the archive from r, which has the given size.
+func Read(r io.ReaderAt, size int64) (*Archive, error) {
+	reader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return parse(reader.File)
+}
+
+func parse(files []*zip.File) (*Archive, error) {
+	archive := Archive{}
+	runs := map[int64]*Run{}
+	run := func(id int64) *Run {
+		if runs[id] == nil {
+			runs[id] = &Run{Id: id}
+		}
+		return runs[id]
+	}
+
+	for _, file := range files {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		dir, name := path.Split(file.Name)
+
+		data, err := readFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case dir == "" && strings.HasPrefix(name, "training_instance"):
+			err = json.Unmarshal(data, &archive.Instance)
+		case dir == "" && strings.HasPrefix(name, "training_definition"):
+			archive.DefinitionContent = string(data)
+		case dir == "training_runs/":
+			var parsed Run
+			err = json.Unmarshal(data, &parsed)
+			if err == nil {
+				existing := run(parsed.Id)
+				parsed.Events = existing.Events
+				*existing = parsed
+			}
+		case dir == "training_events/":
+			var events []kypo.TrainingEvent
+			events, err = parseEvents(data)
+			for _, event := range events {
+				if event.Base().TrainingRunId == 0 {
+					event.Base().TrainingRunId = runIdFromName(name)
+				}
+				runId := event.Base().TrainingRunId
+				run(runId).Events = append(run(runId).Events, event)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Name, err)
+		}
+	}
+
+	for _, r := range runs {
+		kypo.SortTrainingEvents(r.Events)
+		archive.Runs = append(archive.Runs, *r)
+	}
+	sort.Slice(archive.Runs, func(i, j int) bool {
+		return archive.Runs[i].Id < archive.Runs[j].Id
+	})
+
+	return &archive, nil
+}
+
+func readFile(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+func runIdFromName(name string) int64 {
+	match := runIdPattern.FindStringSubmatch(name)
+	if match == nil {
+		return 0
+	}
+	id, _ := strconv.ParseInt(match[1], 10, 64)
+	return id
+}
+
+// parseEvents parses a JSON array of events or events separated by whitespace, one per line.
+func parseEvents(data []byte) ([]kypo.TrainingEvent, error) {
+	data = bytes.TrimSpace(data)
+	var rawEvents []json.RawMessage
+	if bytes.HasPrefix(data, []byte("[")) {
+		err := json.Unmarshal(data, &rawEvents)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		for decoder.More() {
+			var rawEvent json.RawMessage
+			err := decoder.Decode(&rawEvent)
+			if err != nil {
+				return nil, err
+			}
+			rawEvents = append(rawEvents, rawEvent)
+		}
+	}
+
+	events := make([]kypo.TrainingEvent, 0, len(rawEvents))
+	for _, rawEvent := range rawEvents {
+		event, err := kypo.ParseTrainingEvent(rawEvent)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
@@ -0,0 +1,113 @@
+package archive_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vydrazde/kypo-go-client/pkg/archive"
+	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const (
+	instanceFile   = `{"id": 1, "title": "Class 1", "start_time": "2024-02-01T09:00:00Z", "end_time": "2024-02-01T12:00:00Z", "access_token": "class-1234", "pool_id": 2}`
+	definitionFile = `{"title": "Junior hacker", "levels": []}`
+	runFile        = `{"id": 4, "start_time": "2024-02-01T09:05:00Z", "end_time": "2024-02-01T10:00:00Z", "state": "FINISHED", "participant_ref_id": 7, "sandbox_instance_ref_id": "6b1e2c3d"}`
+	eventsFile     = `{"type": "cz.muni.csirt.kypo.events.trainings.LevelCompleted", "timestamp": 1706778600000, "training_run_id": 4, "level": 42}
+{"type": "cz.muni.csirt.kypo.events.trainings.TrainingRunStarted", "timestamp": 1706778300000, "training_run_id": 4, "level": 41}
+`
+)
+
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	buffer := bytes.Buffer{}
+	writer := zip.NewWriter(&buffer)
+	for name, content := range files {
+		file, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = file.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
+
+func TestRead(t *testing.T) {
+	data := zipArchive(t, map[string]string{
+		"training_instance-id1.json":                     instanceFile,
+		"training_definition-id3.json":                   definitionFile,
+		"training_runs/training_run-id4.json":            runFile,
+		"training_events/training_run-id4-events.json":   eventsFile,
+		"training_events/training_run-id5-events.json":   `[{"type": "TrainingRunStarted", "timestamp": "2024-02-01T09:10:00Z", "phase": 11}]`,
+		"training_runs/":                                 "",
+		"command_histories/training_run-id4-useractions": "ls -la",
+	})
+
+	actual, err := archive.Read(bytes.NewReader(data), int64(len(data)))
+
+	require.NoError(t, err)
+	assert.Equal(t, archive.Instance{
+		Id:          1,
+		Title:       "Class 1",
+		StartTime:   time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC),
+		EndTime:     time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC),
+		AccessToken: "class-1234",
+		PoolId:      2,
+	}, actual.Instance)
+	assert.Equal(t, definitionFile, actual.DefinitionContent)
+
+	require.Len(t, actual.Runs, 2)
+	run := actual.Runs[0]
+	assert.Equal(t, int64(4), run.Id)
+	assert.Equal(t, kypo.TrainingRunStateFinished, run.State)
+	assert.Equal(t, int64(7), run.ParticipantRefId)
+	assert.Equal(t, "6b1e2c3d", run.SandboxId)
+	assert.Equal(t, []kypo.TrainingEvent{
+		&kypo.TrainingRunStarted{TrainingEventBase: kypo.TrainingEventBase{Type: "TrainingRunStarted",
+			Timestamp: time.Date(2024, 2, 1, 9, 5, 0, 0, time.UTC), TrainingRunId: 4, LevelId: 41}},
+		&kypo.LevelCompleted{TrainingEventBase: kypo.TrainingEventBase{Type: "LevelCompleted",
+			Timestamp: time.Date(2024, 2, 1, 9, 10, 0, 0, time.UTC), TrainingRunId: 4, LevelId: 42}},
+	}, run.Events)
+
+	assert.Equal(t, int64(5), actual.Runs[1].Id)
+	assert.Equal(t, []kypo.TrainingEvent{
+		&kypo.TrainingRunStarted{TrainingEventBase: kypo.TrainingEventBase{Type: "TrainingRunStarted",
+			Timestamp: time.Date(2024, 2, 1, 9, 10, 0, 0, time.UTC), TrainingRunId: 5, LevelId: 11}},
+	}, actual.Runs[1].Events)
+}
+
+func TestReadInvalidFile(t *testing.T) {
+	data := zipArchive(t, map[string]string{
+		"training_runs/training_run-id4.json": `{"id": "four"}`,
+	})
+
+	actual, err := archive.Read(bytes.NewReader(data), int64(len(data)))
+
+	assert.Nil(t, actual)
+	assert.ErrorContains(t, err, "training_runs/training_run-id4.json: ")
+}
+
+func TestReadNotZip(t *testing.T) {
+	data := []byte("not a zip archive")
+
+	actual, err := archive.Read(bytes.NewReader(data), int64(len(data)))
+
+	assert.Nil(t, actual)
+	assert.Equal(t, zip.ErrFormat, err)
+}
+
+func TestOpen(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "archive.zip")
+	require.NoError(t, os.WriteFile(name, zipArchive(t, map[string]string{
+		"training_instance-id1.json": instanceFile,
+	}), 0o600))
+
+	actual, err := archive.Open(name)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), actual.Instance.Id)
+	assert.Empty(t, actual.Runs)
+}
@@ -5,7 +5,44 @@ import (
 	"fmt"
 )
 
-var ErrNotFound = errors.New("not found")
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrSandboxLocked = errors.New("sandbox is locked")
+
+	// ErrTrainingDefinitionReleased is returned when deleting a released training definition, which is used
+	// by training instances. The instances must be deleted first.
+	ErrTrainingDefinitionReleased = errors.New("training definition is released")
+
+	// ErrSandboxDefinitionMismatch is returned when assigning a sandbox pool to a training instance whose
+	// training definition uses a different sandbox definition than the pool.
+	ErrSandboxDefinitionMismatch = errors.New("sandbox definition of the pool does not match the training definition")
+
+	// ErrSandboxDefinitionUnknown is returned when assigning a sandbox pool to a training instance whose
+	// sandbox definition cannot be determined, so the pool cannot be checked.
+	ErrSandboxDefinitionUnknown = errors.New("sandbox definition of the training instance is unknown")
+
+	// ErrNoPoolAssigned is returned when unassigning a sandbox pool from a training instance without a pool.
+	ErrNoPoolAssigned = errors.New("no sandbox pool is assigned")
+
+	// ErrTrainingRunNotAccessed is returned by the methods of TrainingRunSession and TrainingRunSessionAdaptive
+	// called before a training run was accessed.
+	ErrTrainingRunNotAccessed = errors.New("training run was not accessed")
+
+	// ErrTrainingInstanceNotRunning is returned by ValidateTrainingInstanceAccessToken for access tokens of training
+	// instances which have not started yet or have already ended.
+	ErrTrainingInstanceNotRunning = errors.New("training instance is not running")
+
+	// ErrAnswerRejected is reported by SolveTraining for levels which did not accept the answer or passkey
+	// declared in the training definition.
+	ErrAnswerRejected = errors.New("declared answer was rejected")
+
+	// ErrAnswerUnknown is reported by SolveTraining for levels whose answer is not declared in the training
+	// definition, such as levels with the answer given by a sandbox variable or by a regular expression.
+	ErrAnswerUnknown = errors.New("answer is not declared in the training definition")
+
+	// ErrLevelNotReached is reported by SolveTraining for levels following a failed access level.
+	ErrLevelNotReached = errors.New("level was not reached")
+)
 
 type Error struct {
 	ResourceName string
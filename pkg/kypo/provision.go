@@ -0,0 +1,494 @@
+package kypo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+// ProvisionSpec declaratively describes a class provisioned by ProvisionTraining.
+type ProvisionSpec struct {
+	// SandboxDefinitionUrl is the URL of the GitLab repository of the sandbox definition.
+	SandboxDefinitionUrl string
+	// SandboxDefinitionRev is the Git revision of the sandbox definition.
+	SandboxDefinitionRev string
+	// TrainingDefinitionContent is the content of an exported linear training definition.
+	// Its `sandbox_definition_id` is set to the provisioned sandbox definition.
+	TrainingDefinitionContent string
+	// PoolSize is the maximum size of the sandbox pool and the number of sandboxes allocated in it.
+	PoolSize int64
+	// ReusePoolId is an existing unassigned pool of the sandbox definition, which is used instead of creating
+	// a new pool. Sandboxes already allocated in it count towards PoolSize.
+	ReusePoolId int64
+	// Title of the training instance.
+	Title string
+	// StartTime of the training instance.
+	StartTime time.Time
+	// EndTime of the training instance.
+	EndTime time.Time
+	// AccessTokenPrefix of the training instance.
+	AccessTokenPrefix string
+	// PollTime is the time between checks whether the sandbox allocations have finished.
+	// DefaultProvisionPollTime is used if it is not positive.
+	PollTime time.Duration
+	// Rollback deletes the resources created by ProvisionTraining when a step fails.
+	// Otherwise, the resources are kept and the returned journal can be used to resume the provisioning.
+	Rollback bool
+}
+
+// DefaultProvisionPollTime is the time between checks of sandbox requests used when no positive poll time is given.
+const DefaultProvisionPollTime = 5 * time.Second
+
+// ProvisionJournal records the resources created by ProvisionTraining. It can be stored as JSON and passed
+// to ProvisionTraining to resume a failed provisioning, or to RollbackProvisioning to delete the resources.
+type ProvisionJournal struct {
+	SandboxDefinitionId int64 `json:"sandbox_definition_id"`
+	// SandboxDefinitionReused is set when an existing sandbox definition with the same URL and revision was used,
+	// so it is not deleted by RollbackProvisioning.
+	SandboxDefinitionReused bool  `json:"sandbox_definition_reused"`
+	PoolId                  int64 `json:"pool_id"`
+	// PoolReused is set when the pool given by ProvisionSpec.ReusePoolId was used,
+	// so it is not deleted by RollbackProvisioning.
+	PoolReused bool `json:"pool_reused"`
+	// AllocationUnitIds are the allocation units created by ProvisionTraining. Units which already existed
+	// in a reused pool are not recorded.
+	AllocationUnitIds    []int64 `json:"allocation_unit_ids"`
+	TrainingDefinitionId int64   `json:"training_definition_id"`
+	// TrainingDefinitionReused is set when an existing training definition with equal content was used,
+	// so it is not deleted by RollbackProvisioning.
+	TrainingDefinitionReused bool `json:"training_definition_reused"`
+	// TrainingDefinitionReleased is set when ProvisionTraining changed the state of the training definition
+	// to RELEASED, so RollbackProvisioning reverts the state of a reused training definition.
+	TrainingDefinitionReleased bool  `json:"training_definition_released"`
+	TrainingInstanceId         int64 `json:"training_instance_id"`
+	PoolAssigned               bool  `json:"pool_assigned"`
+}
+
+// ProvisionError is returned by ProvisionTraining when a step fails.
+type ProvisionError struct {
+	// Step which failed.
+	Step string
+	Err  error
+	// RollbackErr is the error of the rollback, if it was requested and failed.
+	RollbackErr error
+}
+
+func (e *ProvisionError) Error() string {
+	if e.RollbackErr != nil {
+		return fmt.Sprintf("provisioning step %s failed: %s; rollback failed: %s", e.Step, e.Err, e.RollbackErr)
+	}
+	return fmt.Sprintf("provisioning step %s failed: %s", e.Step, e.Err)
+}
+
+func (e *ProvisionError) Unwrap() error {
+	return e.Err
+}
+
+// ProvisionTraining provisions a class described by `spec`: it creates the sandbox definition and a sandbox pool,
+// allocates the sandboxes, imports and releases the training definition, creates the training instance and assigns
+// the pool to it. Each created resource is recorded in the returned journal.
+//
+// If `journal` is not nil, the provisioning is resumed. Resources recorded in the journal are checked and reused
+// if they still exist. Resources not recorded in the journal are looked up on the server before they are created:
+// an existing sandbox definition with the same URL and revision and an existing training definition with the same
+// title and equal content are reused. A pool is reused only if it is given by spec.ReusePoolId.
+//
+// When a step fails, a ProvisionError is returned together with the journal. If spec.Rollback is set,
+// the created resources are deleted using RollbackProvisioning first.
+func (c *Client) ProvisionTraining(ctx context.Context, spec ProvisionSpec, journal *ProvisionJournal) (*ProvisionJournal, error) {
+	if journal == nil {
+		journal = &ProvisionJournal{}
+	}
+	if spec.PollTime <= 0 {
+		spec.PollTime = DefaultProvisionPollTime
+	}
+
+	steps := []struct {
+		name string
+		run  func(context.Context, ProvisionSpec, *ProvisionJournal) error
+	}{
+		{"create sandbox definition", c.provisionSandboxDefinition},
+		{"create sandbox pool", c.provisionSandboxPool},
+		{"allocate sandboxes", c.provisionSandboxes},
+		{"create training definition", c.provisionTrainingDefinition},
+		{"create training instance", c.provisionTrainingInstance},
+		{"assign sandbox pool", c.provisionPoolAssignment},
+	}
+	for _, step := range steps {
+		err := step.run(ctx, spec, journal)
+		if err == nil {
+			continue
+		}
+
+		provisionErr := &ProvisionError{Step: step.name, Err: err}
+		if spec.Rollback {
+			provisionErr.RollbackErr = c.RollbackProvisioning(ctx, journal, spec.PollTime)
+		}
+		return journal, provisionErr
+	}
+
+	return journal, nil
+}
+
+func (c *Client) provisionSandboxDefinition(ctx context.Context, spec ProvisionSpec, journal *ProvisionJournal) error {
+	if journal.SandboxDefinitionId != 0 {
+		_, err := c.GetSandboxDefinition(ctx, journal.SandboxDefinitionId)
+		if !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		journal.SandboxDefinitionId = 0
+		journal.SandboxDefinitionReused = false
+	}
+
+	definitions, err := c.ListSandboxDefinitions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, definition := range definitions {
+		if definition.Url == spec.SandboxDefinitionUrl && definition.Rev == spec.SandboxDefinitionRev {
+			journal.SandboxDefinitionId = definition.Id
+			journal.SandboxDefinitionReused = true
+			return nil
+		}
+	}
+
+	definition, err := c.CreateSandboxDefinition(ctx, spec.SandboxDefinitionUrl, spec.SandboxDefinitionRev)
+	if err != nil {
+		return err
+	}
+	journal.SandboxDefinitionId = definition.Id
+	return nil
+}
+
+func (c *Client) provisionSandboxPool(ctx context.Context, spec ProvisionSpec, journal *ProvisionJournal) error {
+	if journal.PoolId != 0 {
+		_, err := c.GetSandboxPool(ctx, journal.PoolId)
+		if !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		journal.PoolId = 0
+		journal.PoolReused = false
+		journal.AllocationUnitIds = nil
+		journal.PoolAssigned = false
+	}
+
+	if spec.ReusePoolId != 0 {
+		pool, err := c.GetSandboxPool(ctx, spec.ReusePoolId)
+		if err != nil {
+			return err
+		}
+		if pool.Definition.Id != journal.SandboxDefinitionId {
+			return &Error{ResourceName: "sandbox pool", Identifier: pool.Id,
+				Err: fmt.Errorf("pool uses sandbox definition %d instead of %d", pool.Definition.Id, journal.SandboxDefinitionId)}
+		}
+		if pool.LockId != 0 {
+			return &Error{ResourceName: "sandbox pool", Identifier: pool.Id, Err: fmt.Errorf("pool is assigned to a training instance")}
+		}
+		journal.PoolId = pool.Id
+		journal.PoolReused = true
+		return nil
+	}
+
+	pool, err := c.CreateSandboxPool(ctx, journal.SandboxDefinitionId, spec.PoolSize)
+	if err != nil {
+		return err
+	}
+	journal.PoolId = pool.Id
+	return nil
+}
+
+func (c *Client) provisionSandboxes(ctx context.Context, spec ProvisionSpec, journal *ProvisionJournal) error {
+	existing := make([]int64, 0, len(journal.AllocationUnitIds))
+	for _, unitId := range journal.AllocationUnitIds {
+		_, err := c.GetSandboxAllocationUnit(ctx, unitId)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		existing = append(existing, unitId)
+	}
+	journal.AllocationUnitIds = existing
+
+	missing := spec.PoolSize - int64(len(journal.AllocationUnitIds))
+	if journal.PoolReused {
+		pool, err := c.GetSandboxPool(ctx, journal.PoolId)
+		if err != nil {
+			return err
+		}
+		missing = spec.PoolSize - pool.Size
+	}
+	if missing > 0 {
+		units, err := c.CreateSandboxAllocationUnits(ctx, journal.PoolId, missing)
+		if err != nil {
+			return err
+		}
+		for _, unit := range units {
+			journal.AllocationUnitIds = append(journal.AllocationUnitIds, unit.Id)
+		}
+	}
+
+	for _, unitId := range journal.AllocationUnitIds {
+		err := c.AwaitAllocationRequestCreate(ctx, unitId, spec.PollTime)
+		if err != nil {
+			return err
+		}
+		request, err := c.PollRequestFinished(ctx, unitId, spec.PollTime, "allocation")
+		if err != nil {
+			return err
+		}
+		if slices.Contains(request.Stages, "FAILED") {
+			return &Error{ResourceName: "sandbox allocation request", Identifier: fmt.Sprintf("sandbox allocation unit %d", unitId),
+				Err: fmt.Errorf("sandbox allocation request finished with error")}
+		}
+	}
+	return nil
+}
+
+func (c *Client) provisionTrainingDefinition(ctx context.Context, spec ProvisionSpec, journal *ProvisionJournal) error {
+	definitionSpec, err := ParseLinearTrainingDefinition(spec.TrainingDefinitionContent)
+	if err != nil {
+		return err
+	}
+	definitionSpec.SandboxDefinitionId = journal.SandboxDefinitionId
+	content, err := definitionSpec.Content()
+	if err != nil {
+		return err
+	}
+
+	if journal.TrainingDefinitionId != 0 {
+		_, err := c.GetTrainingDefinition(ctx, journal.TrainingDefinitionId)
+		if err == nil {
+			return c.releaseTrainingDefinition(ctx, journal)
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		journal.TrainingDefinitionId = 0
+		journal.TrainingDefinitionReused = false
+		journal.TrainingDefinitionReleased = false
+	}
+
+	id, err := c.findTrainingDefinition(ctx, definitionSpec.Title, content)
+	if err != nil {
+		return err
+	}
+	if id != 0 {
+		journal.TrainingDefinitionId = id
+		journal.TrainingDefinitionReused = true
+		return c.releaseTrainingDefinition(ctx, journal)
+	}
+
+	definition, err := c.CreateTrainingDefinition(ctx, content)
+	if err != nil {
+		return err
+	}
+	journal.TrainingDefinitionId = definition.Id
+	return c.releaseTrainingDefinition(ctx, journal)
+}
+
+// findTrainingDefinition returns the id of a training definition with the given title and content equal to `content`,
+// or 0 if there is none.
+func (c *Client) findTrainingDefinition(ctx context.Context, title, content string) (int64, error) {
+	for page := int64(1); ; page++ {
+		definitions, err := c.ListTrainingDefinitions(ctx, ListTrainingDefinitionsOptions{Page: page, PageSize: javaPageSize, Title: title})
+		if err != nil {
+			return 0, err
+		}
+		for _, summary := range definitions.Results {
+			if summary.Title != title || summary.State == TrainingDefinitionStateArchived {
+				continue
+			}
+			definition, err := c.GetTrainingDefinition(ctx, summary.Id)
+			if err != nil {
+				return 0, err
+			}
+			equal, err := EqualContent(stripState(definition.Content), stripState(content))
+			if err != nil {
+				return 0, err
+			}
+			if equal {
+				return summary.Id, nil
+			}
+		}
+		if page >= definitions.PageCount {
+			return 0, nil
+		}
+	}
+}
+
+// stripState removes the state from the training definition content, so definitions which differ only by their
+// state are considered equal. Invalid content is returned unchanged.
+func stripState(content string) string {
+	spec, err := ParseLinearTrainingDefinition(content)
+	if err != nil {
+		return content
+	}
+	spec.State = ""
+	stripped, err := spec.Content()
+	if err != nil {
+		return content
+	}
+	return stripped
+}
+
+// releaseTrainingDefinition releases the training definition of the journal and records whether its state was changed.
+func (c *Client) releaseTrainingDefinition(ctx context.Context, journal *ProvisionJournal) error {
+	state, err := c.GetTrainingDefinitionState(ctx, journal.TrainingDefinitionId)
+	if err != nil {
+		return err
+	}
+	if state == TrainingDefinitionStateReleased {
+		return nil
+	}
+	err = c.SetTrainingDefinitionState(ctx, journal.TrainingDefinitionId, TrainingDefinitionStateReleased)
+	if err != nil {
+		return err
+	}
+	journal.TrainingDefinitionReleased = true
+	return nil
+}
+
+func (c *Client) provisionTrainingInstance(ctx context.Context, spec ProvisionSpec, journal *ProvisionJournal) error {
+	if journal.TrainingInstanceId != 0 {
+		instance, err := c.GetTrainingInstance(ctx, journal.TrainingInstanceId)
+		if err == nil {
+			journal.PoolAssigned = instance.PoolId == journal.PoolId
+			return nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		journal.PoolAssigned = false
+	}
+
+	instance, err := c.CreateTrainingInstance(ctx, TrainingInstanceRequest{
+		Title:                spec.Title,
+		StartTime:            spec.StartTime,
+		EndTime:              spec.EndTime,
+		AccessTokenPrefix:    spec.AccessTokenPrefix,
+		TrainingDefinitionId: journal.TrainingDefinitionId,
+	})
+	if err != nil {
+		return err
+	}
+	journal.TrainingInstanceId = instance.Id
+	return nil
+}
+
+func (c *Client) provisionPoolAssignment(ctx context.Context, _ ProvisionSpec, journal *ProvisionJournal) error {
+	if journal.PoolAssigned {
+		return nil
+	}
+
+	_, err := c.AssignPoolToTrainingInstance(ctx, journal.TrainingInstanceId, journal.PoolId)
+	if err != nil {
+		return err
+	}
+	journal.PoolAssigned = true
+	return nil
+}
+
+// RollbackProvisioning deletes the resources recorded in the `journal` by ProvisionTraining in the reverse order
+// of their creation. A reused sandbox definition, pool or training definition is kept, but a reused training definition
+// released by ProvisionTraining is unreleased again. Each deleted resource is removed from the journal,
+// so a failed rollback can be retried. The sandboxes are cleaned up and the cleanup is checked once every `pollTime`,
+// or every DefaultProvisionPollTime if `pollTime` is not positive.
+func (c *Client) RollbackProvisioning(ctx context.Context, journal *ProvisionJournal, pollTime time.Duration) error {
+	if pollTime <= 0 {
+		pollTime = DefaultProvisionPollTime
+	}
+
+	if journal.PoolAssigned {
+		_, err := c.UnassignPoolFromTrainingInstance(ctx, journal.TrainingInstanceId)
+		if err != nil && !errors.Is(err, ErrNotFound) && !errors.Is(err, ErrNoPoolAssigned) {
+			return err
+		}
+		journal.PoolAssigned = false
+	}
+
+	if journal.TrainingInstanceId != 0 {
+		err := c.DeleteTrainingInstance(ctx, journal.TrainingInstanceId, true)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		journal.TrainingInstanceId = 0
+	}
+
+	if journal.TrainingDefinitionId != 0 {
+		if !journal.TrainingDefinitionReused {
+			err := c.deleteProvisionedTrainingDefinition(ctx, journal.TrainingDefinitionId)
+			if err != nil {
+				return err
+			}
+		} else if journal.TrainingDefinitionReleased {
+			err := c.SetTrainingDefinitionState(ctx, journal.TrainingDefinitionId, TrainingDefinitionStateUnreleased)
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				return err
+			}
+		}
+		journal.TrainingDefinitionId = 0
+		journal.TrainingDefinitionReused = false
+		journal.TrainingDefinitionReleased = false
+	}
+
+	for len(journal.AllocationUnitIds) > 0 {
+		unitId := journal.AllocationUnitIds[len(journal.AllocationUnitIds)-1]
+		err := c.CreateSandboxCleanupRequestAwait(ctx, unitId, pollTime)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		journal.AllocationUnitIds = journal.AllocationUnitIds[:len(journal.AllocationUnitIds)-1]
+	}
+
+	if journal.PoolId != 0 {
+		if !journal.PoolReused {
+			err := c.DeleteSandboxPool(ctx, journal.PoolId)
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				return err
+			}
+		}
+		journal.PoolId = 0
+		journal.PoolReused = false
+	}
+
+	if journal.SandboxDefinitionId != 0 {
+		if !journal.SandboxDefinitionReused {
+			err := c.DeleteSandboxDefinition(ctx, journal.SandboxDefinitionId)
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				return err
+			}
+		}
+		journal.SandboxDefinitionId = 0
+		journal.SandboxDefinitionReused = false
+	}
+
+	return nil
+}
+
+// deleteProvisionedTrainingDefinition deletes a training definition, which has to be unreleased first.
+func (c *Client) deleteProvisionedTrainingDefinition(ctx context.Context, definitionID int64) error {
+	state, err := c.GetTrainingDefinitionState(ctx, definitionID)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if state == TrainingDefinitionStateReleased {
+		err = c.SetTrainingDefinitionState(ctx, definitionID, TrainingDefinitionStateUnreleased)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = c.DeleteTrainingDefinition(ctx, definitionID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return nil
+}
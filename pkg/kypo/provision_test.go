@@ -0,0 +1,488 @@
+package kypo_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	sandboxService  = "/kypo-sandbox-service/api/v1"
+	trainingService = "/kypo-rest-training/api/v1"
+)
+
+// fakeKypo simulates the sandbox and training services for a single provisioned class.
+// It creates the sandbox definition 10, the pool 11, the allocation units 12 and 13,
+// the training definition 20 and the training instance 30.
+type fakeKypo struct {
+	t     *testing.T
+	mutex sync.Mutex
+
+	// failOn is a request which fails with the status 500.
+	failOn string
+	// existingDefinition is the content of a training definition 25 returned by the search.
+	existingDefinition string
+	// existingDefinitionState is the state of the training definition 25.
+	existingDefinitionState string
+
+	requests           []string
+	sandboxDefinition  bool
+	pool               bool
+	units              map[int64]bool
+	trainingDefinition string
+	definitionState    string
+	instance           bool
+	assignedPool       int64
+}
+
+func newFakeKypo(t *testing.T) *fakeKypo {
+	return &fakeKypo{t: t, units: map[int64]bool{}, existingDefinitionState: "RELEASED"}
+}
+
+func (f *fakeKypo) count(request string) int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	count := 0
+	for _, r := range f.requests {
+		if r == request {
+			count++
+		}
+	}
+	return count
+}
+
+func (f *fakeKypo) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	key := request.Method + " " + request.URL.Path
+	f.requests = append(f.requests, key)
+	if key == f.failOn {
+		writer.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	body, _ := io.ReadAll(request.Body)
+
+	notFound := func(exists bool) bool {
+		if !exists {
+			writer.WriteHeader(http.StatusNotFound)
+		}
+		return !exists
+	}
+
+	switch key {
+	case "GET " + sandboxService + "/definitions":
+		var results []string
+		if f.sandboxDefinition {
+			results = append(results, `{"id": 10, "url": "url", "rev": "rev"}`)
+		}
+		f.writeSandboxPage(writer, results)
+	case "POST " + sandboxService + "/definitions":
+		f.sandboxDefinition = true
+		writer.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprint(writer, `{"id": 10, "url": "url", "rev": "rev"}`)
+	case "GET " + sandboxService + "/definitions/10":
+		if !notFound(f.sandboxDefinition) {
+			_, _ = fmt.Fprint(writer, `{"id": 10, "url": "url", "rev": "rev"}`)
+		}
+	case "DELETE " + sandboxService + "/definitions/10":
+		f.sandboxDefinition = false
+		writer.WriteHeader(http.StatusNoContent)
+	case "POST " + sandboxService + "/pools":
+		assert.JSONEq(f.t, `{"definition_id": 10, "max_size": 2}`, string(body))
+		f.pool = true
+		writer.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprint(writer, `{"id": 11, "max_size": 2, "definition": {"id": 10}}`)
+	case "GET " + sandboxService + "/pools/11":
+		if !notFound(f.pool) {
+			_, _ = fmt.Fprint(writer, f.poolResponse())
+		}
+	case "DELETE " + sandboxService + "/pools/11":
+		f.pool = false
+		writer.WriteHeader(http.StatusNoContent)
+	case "POST " + sandboxService + "/pools/11/sandbox-allocation-units":
+		var results []string
+		for id := int64(12); id < 12+2 && len(results) < 2; id++ {
+			if f.units[id] {
+				continue
+			}
+			f.units[id] = true
+			results = append(results, fmt.Sprintf(`{"id": %d, "pool_id": 11}`, id))
+		}
+		assert.Equal(f.t, fmt.Sprint(len(results)), request.URL.Query().Get("count"))
+		f.writeSandboxPage(writer, results)
+	case "GET " + sandboxService + "/sandbox-allocation-units/12", "GET " + sandboxService + "/sandbox-allocation-units/13":
+		if !notFound(f.units[f.unitId(request.URL.Path, 0)]) {
+			_, _ = fmt.Fprint(writer, `{"id": 12, "pool_id": 11}`)
+		}
+	case "GET " + sandboxService + "/sandbox-allocation-units/12/allocation-request",
+		"GET " + sandboxService + "/sandbox-allocation-units/13/allocation-request":
+		_, _ = fmt.Fprint(writer, `{"id": 1, "stages": ["FINISHED", "FINISHED", "FINISHED"]}`)
+	case "POST " + sandboxService + "/sandbox-allocation-units/12/cleanup-request",
+		"POST " + sandboxService + "/sandbox-allocation-units/13/cleanup-request":
+		delete(f.units, f.unitId(request.URL.Path, 1))
+		writer.WriteHeader(http.StatusCreated)
+	case "GET " + sandboxService + "/sandbox-allocation-units/12/cleanup-request",
+		"GET " + sandboxService + "/sandbox-allocation-units/13/cleanup-request":
+		writer.WriteHeader(http.StatusNotFound)
+	case "GET " + trainingService + "/training-definitions":
+		assert.Equal(f.t, "Junior hacker", request.URL.Query().Get("title"))
+		content := ""
+		if f.existingDefinition != "" {
+			content = fmt.Sprintf(`{"id": 25, "title": "Junior hacker", "state": %q}`, f.existingDefinitionState)
+		}
+		_, _ = fmt.Fprintf(writer, `{"content": [%s], "pagination": {"number": 0, "number_of_elements": 1, "size": 100,
+			"total_elements": 1, "total_pages": 1}}`, content)
+	case "GET " + trainingService + "/exports/training-definitions/25":
+		_, _ = fmt.Fprint(writer, f.existingDefinition)
+	case "GET " + trainingService + "/training-definitions/25":
+		_, _ = fmt.Fprintf(writer, `{"id": 25, "state": %q}`, f.existingDefinitionState)
+	case "PUT " + trainingService + "/training-definitions/25/states/RELEASED":
+		f.existingDefinitionState = "RELEASED"
+		writer.WriteHeader(http.StatusNoContent)
+	case "PUT " + trainingService + "/training-definitions/25/states/UNRELEASED":
+		f.existingDefinitionState = "UNRELEASED"
+		writer.WriteHeader(http.StatusNoContent)
+	case "POST " + trainingService + "/imports/training-definitions":
+		f.trainingDefinition = string(body)
+		f.definitionState = "UNRELEASED"
+		_, _ = fmt.Fprint(writer, `{"id": 20}`)
+	case "GET " + trainingService + "/exports/training-definitions/20":
+		if !notFound(f.trainingDefinition != "") {
+			_, _ = fmt.Fprint(writer, f.trainingDefinition)
+		}
+	case "GET " + trainingService + "/training-definitions/20":
+		if !notFound(f.trainingDefinition != "") {
+			_, _ = fmt.Fprintf(writer, `{"id": 20, "state": %q}`, f.definitionState)
+		}
+	case "PUT " + trainingService + "/training-definitions/20/states/RELEASED":
+		f.definitionState = "RELEASED"
+		writer.WriteHeader(http.StatusNoContent)
+	case "PUT " + trainingService + "/training-definitions/20/states/UNRELEASED":
+		f.definitionState = "UNRELEASED"
+		writer.WriteHeader(http.StatusNoContent)
+	case "DELETE " + trainingService + "/training-definitions/20":
+		f.trainingDefinition = ""
+	case "POST " + trainingService + "/training-instances":
+		assert.Contains(f.t, string(body), `"access_token":"class"`)
+		f.instance = true
+		_, _ = fmt.Fprint(writer, f.instanceResponse())
+	case "GET " + trainingService + "/training-instances/30":
+		if !notFound(f.instance) {
+			_, _ = fmt.Fprint(writer, f.instanceResponse())
+		}
+	case "DELETE " + trainingService + "/training-instances/30":
+		f.instance = false
+	case "PATCH " + trainingService + "/training-instances/30/assign-pool":
+		f.assignedPool = 11
+	case "PATCH " + trainingService + "/training-instances/30/unassign-pool":
+		f.assignedPool = 0
+	default:
+		f.t.Errorf("unexpected request %s", key)
+		writer.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func (f *fakeKypo) unitId(path string, suffixes int) int64 {
+	parts := strings.Split(path, "/")
+	var id int64
+	_, _ = fmt.Sscan(parts[len(parts)-1-suffixes], &id)
+	return id
+}
+
+func (f *fakeKypo) writeSandboxPage(writer http.ResponseWriter, results []string) {
+	_, _ = fmt.Fprintf(writer, `{"page": 1, "page_size": 10, "page_count": 1, "count": %d, "total_count": %d, "results": [%s]}`,
+		len(results), len(results), strings.Join(results, ","))
+}
+
+func (f *fakeKypo) poolResponse() string {
+	return fmt.Sprintf(`{"id": 11, "size": %d, "max_size": 2, "lock_id": %d, "definition": {"id": 10}}`, len(f.units), f.assignedPool)
+}
+
+func (f *fakeKypo) instanceResponse() string {
+	definitionId := 20
+	if f.existingDefinition != "" {
+		definitionId = 25
+	}
+	return fmt.Sprintf(`{"id": 30, "title": "Class", "access_token": "class-1234", "pool_id": %d,
+		"training_definition": {"id": %d}}`, f.assignedPool, definitionId)
+}
+
+var provisionSpec = kypo.ProvisionSpec{
+	SandboxDefinitionUrl:      "url",
+	SandboxDefinitionRev:      "rev",
+	TrainingDefinitionContent: `{"title": "Junior hacker", "state": "UNRELEASED", "levels": []}`,
+	PoolSize:                  2,
+	Title:                     "Class",
+	StartTime:                 time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC),
+	EndTime:                   time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC),
+	AccessTokenPrefix:         "class",
+	PollTime:                  time.Millisecond,
+}
+
+func TestProvisionTrainingSuccessful(t *testing.T) {
+	fake := newFakeKypo(t)
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := kypo.ProvisionJournal{
+		SandboxDefinitionId:        10,
+		PoolId:                     11,
+		AllocationUnitIds:          []int64{12, 13},
+		TrainingDefinitionId:       20,
+		TrainingDefinitionReleased: true,
+		TrainingInstanceId:         30,
+		PoolAssigned:               true,
+	}
+
+	actual, err := c.ProvisionTraining(context.Background(), provisionSpec, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expected, actual)
+	assert.JSONEq(t, `{"title": "Junior hacker", "state": "UNRELEASED", "levels": [], "sandbox_definition_id": 10}`, fake.trainingDefinition)
+	assert.Equal(t, "RELEASED", fake.definitionState)
+	assert.Equal(t, int64(11), fake.assignedPool)
+}
+
+func TestProvisionTrainingReuseDefinition(t *testing.T) {
+	fake := newFakeKypo(t)
+	fake.existingDefinition = `{"title": "Junior hacker", "state": "RELEASED", "levels": [], "sandbox_definition_id": 10}`
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.ProvisionTraining(context.Background(), provisionSpec, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(25), actual.TrainingDefinitionId)
+	assert.True(t, actual.TrainingDefinitionReused)
+	assert.False(t, actual.TrainingDefinitionReleased)
+	assert.Equal(t, 0, fake.count("POST "+trainingService+"/imports/training-definitions"))
+}
+
+func TestProvisionTrainingRollbackUnreleasesReusedDefinition(t *testing.T) {
+	fake := newFakeKypo(t)
+	fake.existingDefinition = `{"title": "Junior hacker", "state": "UNRELEASED", "levels": [], "sandbox_definition_id": 10}`
+	fake.existingDefinitionState = "UNRELEASED"
+	fake.failOn = "PATCH " + trainingService + "/training-instances/30/assign-pool"
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	spec := provisionSpec
+	spec.Rollback = true
+
+	_, err := c.ProvisionTraining(context.Background(), spec, nil)
+
+	var provisionErr *kypo.ProvisionError
+	require.ErrorAs(t, err, &provisionErr)
+	assert.Nil(t, provisionErr.RollbackErr)
+	assert.Equal(t, "UNRELEASED", fake.existingDefinitionState)
+	assert.Equal(t, 1, fake.count("PUT "+trainingService+"/training-definitions/25/states/RELEASED"))
+	assert.Equal(t, 1, fake.count("PUT "+trainingService+"/training-definitions/25/states/UNRELEASED"))
+	assert.Equal(t, 0, fake.count("DELETE "+trainingService+"/training-definitions/25"))
+}
+
+func TestProvisionTrainingReuseSandboxResources(t *testing.T) {
+	fake := newFakeKypo(t)
+	fake.sandboxDefinition = true
+	fake.pool = true
+	fake.units[12] = true
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	spec := provisionSpec
+	spec.ReusePoolId = 11
+
+	actual, err := c.ProvisionTraining(context.Background(), spec, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), actual.SandboxDefinitionId)
+	assert.True(t, actual.SandboxDefinitionReused)
+	assert.Equal(t, int64(11), actual.PoolId)
+	assert.True(t, actual.PoolReused)
+	assert.Equal(t, []int64{13}, actual.AllocationUnitIds)
+	assert.Equal(t, 0, fake.count("POST "+sandboxService+"/definitions"))
+	assert.Equal(t, 0, fake.count("POST "+sandboxService+"/pools"))
+
+	err = c.RollbackProvisioning(context.Background(), actual, time.Millisecond)
+
+	assert.NoError(t, err)
+	assert.True(t, fake.sandboxDefinition)
+	assert.True(t, fake.pool)
+	assert.Equal(t, map[int64]bool{12: true}, fake.units)
+	assert.Equal(t, 0, fake.count("DELETE "+sandboxService+"/pools/11"))
+	assert.Equal(t, 0, fake.count("DELETE "+sandboxService+"/definitions/10"))
+}
+
+func TestProvisionTrainingExistingPoolNotReused(t *testing.T) {
+	fake := newFakeKypo(t)
+	fake.pool = true
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.ProvisionTraining(context.Background(), provisionSpec, nil)
+
+	require.NoError(t, err)
+	assert.False(t, actual.PoolReused)
+	assert.Equal(t, 1, fake.count("POST "+sandboxService+"/pools"))
+}
+
+func TestProvisionTrainingReusePoolAssigned(t *testing.T) {
+	fake := newFakeKypo(t)
+	fake.sandboxDefinition = true
+	fake.pool = true
+	fake.assignedPool = 11
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	spec := provisionSpec
+	spec.ReusePoolId = 11
+
+	actual, err := c.ProvisionTraining(context.Background(), spec, nil)
+
+	var provisionErr *kypo.ProvisionError
+	require.ErrorAs(t, err, &provisionErr)
+	assert.Equal(t, "create sandbox pool", provisionErr.Step)
+	assert.EqualError(t, provisionErr.Err, "resource sandbox pool 11: pool is assigned to a training instance")
+	assert.Zero(t, actual.PoolId)
+}
+
+func TestProvisionTrainingResume(t *testing.T) {
+	fake := newFakeKypo(t)
+	fake.failOn = "POST " + trainingService + "/training-instances"
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	journal, err := c.ProvisionTraining(context.Background(), provisionSpec, nil)
+
+	var provisionErr *kypo.ProvisionError
+	require.ErrorAs(t, err, &provisionErr)
+	assert.Equal(t, "create training instance", provisionErr.Step)
+	assert.Nil(t, provisionErr.RollbackErr)
+	assert.Equal(t, &kypo.ProvisionJournal{
+		SandboxDefinitionId:        10,
+		PoolId:                     11,
+		AllocationUnitIds:          []int64{12, 13},
+		TrainingDefinitionId:       20,
+		TrainingDefinitionReleased: true,
+	}, journal)
+
+	fake.failOn = ""
+	actual, err := c.ProvisionTraining(context.Background(), provisionSpec, journal)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(30), actual.TrainingInstanceId)
+	assert.True(t, actual.PoolAssigned)
+	assert.Equal(t, 1, fake.count("POST "+sandboxService+"/definitions"))
+	assert.Equal(t, 1, fake.count("POST "+sandboxService+"/pools"))
+	assert.Equal(t, 1, fake.count("POST "+sandboxService+"/pools/11/sandbox-allocation-units"))
+	assert.Equal(t, 1, fake.count("POST "+trainingService+"/imports/training-definitions"))
+}
+
+func TestProvisionTrainingResumeMissingUnit(t *testing.T) {
+	fake := newFakeKypo(t)
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	journal, err := c.ProvisionTraining(context.Background(), provisionSpec, nil)
+	require.NoError(t, err)
+
+	delete(fake.units, 12)
+	actual, err := c.ProvisionTraining(context.Background(), provisionSpec, journal)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{13, 12}, actual.AllocationUnitIds)
+	assert.Equal(t, 2, fake.count("POST "+sandboxService+"/pools/11/sandbox-allocation-units"))
+	assert.Equal(t, 1, fake.count("PATCH "+trainingService+"/training-instances/30/assign-pool"))
+}
+
+func TestProvisionTrainingRollback(t *testing.T) {
+	fake := newFakeKypo(t)
+	fake.failOn = "PATCH " + trainingService + "/training-instances/30/assign-pool"
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	spec := provisionSpec
+	spec.Rollback = true
+
+	actual, err := c.ProvisionTraining(context.Background(), spec, nil)
+
+	var provisionErr *kypo.ProvisionError
+	require.ErrorAs(t, err, &provisionErr)
+	assert.Equal(t, "assign sandbox pool", provisionErr.Step)
+	assert.Nil(t, provisionErr.RollbackErr)
+	assert.Equal(t, &kypo.ProvisionJournal{AllocationUnitIds: []int64{}}, actual)
+	assert.False(t, fake.sandboxDefinition)
+	assert.False(t, fake.pool)
+	assert.Empty(t, fake.units)
+	assert.Empty(t, fake.trainingDefinition)
+	assert.False(t, fake.instance)
+	assert.Equal(t, 1, fake.count("PUT "+trainingService+"/training-definitions/20/states/UNRELEASED"))
+}
+
+func TestRollbackProvisioningKeepsReusedDefinition(t *testing.T) {
+	fake := newFakeKypo(t)
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	journal := kypo.ProvisionJournal{TrainingDefinitionId: 20, TrainingDefinitionReused: true}
+
+	err := c.RollbackProvisioning(context.Background(), &journal, time.Millisecond)
+
+	assert.NoError(t, err)
+	assert.Equal(t, kypo.ProvisionJournal{}, journal)
+	assert.Empty(t, fake.requests)
+}
+
+func TestRollbackProvisioningDefaultPollTime(t *testing.T) {
+	fake := newFakeKypo(t)
+	fake.units[12] = true
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	journal := kypo.ProvisionJournal{AllocationUnitIds: []int64{12}}
+
+	err := c.RollbackProvisioning(ctx, &journal, 0)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, []int64{12}, journal.AllocationUnitIds)
+}
+
+func TestProvisionErrorMessage(t *testing.T) {
+	err := &kypo.ProvisionError{Step: "create sandbox pool", Err: kypo.ErrNotFound, RollbackErr: fmt.Errorf("status: 500")}
+
+	assert.EqualError(t, err, "provisioning step create sandbox pool failed: not found; rollback failed: status: 500")
+	assert.ErrorIs(t, err, kypo.ErrNotFound)
+}
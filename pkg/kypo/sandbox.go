@@ -0,0 +1,137 @@
+package kypo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type Sandbox struct {
+	Id               string `json:"id" tfsdk:"id"`
+	LockId           int64  `json:"lock_id" tfsdk:"lock_id"`
+	AllocationUnitId int64  `json:"allocation_unit_id" tfsdk:"allocation_unit_id"`
+}
+
+type SandboxLock struct {
+	Id        int64  `json:"id" tfsdk:"id"`
+	SandboxId string `json:"sandbox_id" tfsdk:"sandbox_id"`
+}
+
+// GetSandbox reads the given sandbox.
+func (c *Client) GetSandbox(ctx context.Context, sandboxId string) (*Sandbox, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/kypo-sandbox-service/api/v1/sandboxes/%s", c.Endpoint, sandboxId), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, _, err := c.doRequestWithRetry(req, http.StatusOK, "sandbox", sandboxId)
+	if err != nil {
+		return nil, err
+	}
+
+	sandbox := Sandbox{}
+	err = json.Unmarshal(body, &sandbox)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sandbox, nil
+}
+
+// GetSandboxByAllocationUnit reads the sandbox created by the given sandbox allocation unit.
+func (c *Client) GetSandboxByAllocationUnit(ctx context.Context, unitId int64) (*Sandbox, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/kypo-sandbox-service/api/v1/sandbox-allocation-units/%d/sandbox", c.Endpoint, unitId), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, _, err := c.doRequestWithRetry(req, http.StatusOK, "sandbox", fmt.Sprintf("sandbox allocation unit %d", unitId))
+	if err != nil {
+		return nil, err
+	}
+
+	sandbox := Sandbox{}
+	err = json.Unmarshal(body, &sandbox)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sandbox, nil
+}
+
+// GetSandboxTopology reads the topology of the given sandbox.
+// Unlike the topology of a sandbox definition, the ports contain the IP addresses assigned in the sandbox.
+func (c *Client) GetSandboxTopology(ctx context.Context, sandboxId string) (*Topology, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/kypo-sandbox-service/api/v1/sandboxes/%s/topology", c.Endpoint, sandboxId), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, _, err := c.doRequestWithRetry(req, http.StatusOK, "sandbox topology", sandboxId)
+	if err != nil {
+		return nil, err
+	}
+
+	topology := Topology{}
+	err = json.Unmarshal(body, &topology)
+	if err != nil {
+		return nil, err
+	}
+
+	return &topology, nil
+}
+
+// LockSandbox locks the given sandbox, which reserves it and protects it from being cleaned up.
+func (c *Client) LockSandbox(ctx context.Context, sandboxId string) (*SandboxLock, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/kypo-sandbox-service/api/v1/sandboxes/%s/lock", c.Endpoint, sandboxId), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, _, err := c.doRequestWithRetry(req, http.StatusCreated, "sandbox lock", sandboxId)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := SandboxLock{}
+	err = json.Unmarshal(body, &lock)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lock, nil
+}
+
+// UnlockSandbox removes the lock of the given sandbox.
+func (c *Client) UnlockSandbox(ctx context.Context, sandboxId string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/kypo-sandbox-service/api/v1/sandboxes/%s/lock", c.Endpoint, sandboxId), nil)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = c.doRequestWithRetry(req, http.StatusNoContent, "sandbox lock", sandboxId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListLockedSandboxes reads the sandboxes of the given sandbox pool which are locked.
+func (c *Client) ListLockedSandboxes(ctx context.Context, poolId int64) ([]Sandbox, error) {
+	sandboxes, err := getAllSandboxPages[Sandbox](ctx, c, fmt.Sprintf("%s/kypo-sandbox-service/api/v1/pools/%d/sandboxes", c.Endpoint, poolId),
+		"sandboxes", fmt.Sprintf("sandbox pool %d", poolId))
+	if err != nil {
+		return nil, err
+	}
+
+	locked := make([]Sandbox, 0, len(sandboxes))
+	for _, sandbox := range sandboxes {
+		if sandbox.LockId != 0 {
+			locked = append(locked, sandbox)
+		}
+	}
+
+	return locked, nil
+}
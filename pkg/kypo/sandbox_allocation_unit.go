@@ -1,10 +1,12 @@
 package kypo
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -47,6 +49,10 @@ type SandboxRequestStageOutput struct {
 	Result     string `json:"result" tfsdk:"result"`
 }
 
+type allocationRequest struct {
+	Variables map[string]string `json:"variables"`
+}
+
 type outputLine struct {
 	Content string `json:"content"`
 }
@@ -75,7 +81,42 @@ func (c *Client) GetSandboxAllocationUnit(ctx context.Context, unitId int64) (*S
 
 // CreateSandboxAllocationUnits starts the allocation of `count` sandboxes in the sandbox pool specified by `poolId`.
 func (c *Client) CreateSandboxAllocationUnits(ctx context.Context, poolId, count int64) ([]SandboxAllocationUnit, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/kypo-sandbox-service/api/v1/pools/%d/sandbox-allocation-units?count=%d", c.Endpoint, poolId, count), nil)
+	return c.createSandboxAllocationUnits(ctx, poolId, count, nil)
+}
+
+// CreateSandboxAllocationUnitsWithVariables starts the allocation like CreateSandboxAllocationUnits and passes the values
+// of sandbox definition variables to the allocated sandboxes. The values are validated against the variables declared
+// by the sandbox definition of the pool.
+func (c *Client) CreateSandboxAllocationUnitsWithVariables(ctx context.Context, poolId, count int64, variables map[string]string) ([]SandboxAllocationUnit, error) {
+	pool, err := c.GetSandboxPool(ctx, poolId)
+	if err != nil {
+		return nil, err
+	}
+
+	declared, err := c.GetSandboxDefinitionVariables(ctx, pool.Definition.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ValidateSandboxDefinitionVariables(declared, variables)
+	if err != nil {
+		return nil, &Error{ResourceName: "sandbox allocation units", Identifier: fmt.Sprintf("sandbox pool %d", poolId), Err: err}
+	}
+
+	return c.createSandboxAllocationUnits(ctx, poolId, count, variables)
+}
+
+func (c *Client) createSandboxAllocationUnits(ctx context.Context, poolId, count int64, variables map[string]string) ([]SandboxAllocationUnit, error) {
+	var requestBody io.Reader
+	if variables != nil {
+		encoded, err := marshalObject(allocationRequest{variables}, nil)
+		if err != nil {
+			return nil, err
+		}
+		requestBody = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/kypo-sandbox-service/api/v1/pools/%d/sandbox-allocation-units?count=%d", c.Endpoint, poolId, count), requestBody)
 	if err != nil {
 		return nil, err
 	}
@@ -143,7 +184,13 @@ func (c *Client) CreateSandboxAllocationUnitAwait(ctx context.Context, poolId in
 }
 
 // CreateSandboxCleanupRequest starts a cleanup request for the specified sandbox allocation unit.
+// Locked sandbox allocation units are reserved, for example by a training instance, and their cleanup is refused
+// with ErrSandboxLocked. Use CreateSandboxCleanupRequestWithOptions to clean up a locked unit.
 func (c *Client) CreateSandboxCleanupRequest(ctx context.Context, unitId int64) error {
+	return c.CreateSandboxCleanupRequestWithOptions(ctx, unitId, SandboxCleanupOptions{})
+}
+
+func (c *Client) createSandboxCleanupRequest(ctx context.Context, unitId int64) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/kypo-sandbox-service/api/v1/sandbox-allocation-units/%d/cleanup-request", c.Endpoint, unitId), nil)
 	if err != nil {
 		return err
@@ -153,6 +200,28 @@ func (c *Client) CreateSandboxCleanupRequest(ctx context.Context, unitId int64)
 	return err
 }
 
+// SandboxCleanupOptions are the options of CreateSandboxCleanupRequestWithOptions.
+type SandboxCleanupOptions struct {
+	// IgnoreLock allows the cleanup of locked sandbox allocation units.
+	IgnoreLock bool
+}
+
+// CreateSandboxCleanupRequestWithOptions starts a cleanup request for the specified sandbox allocation unit like
+// CreateSandboxCleanupRequest. The cleanup of a locked unit is refused with ErrSandboxLocked unless `options.IgnoreLock` is set.
+func (c *Client) CreateSandboxCleanupRequestWithOptions(ctx context.Context, unitId int64, options SandboxCleanupOptions) error {
+	if !options.IgnoreLock {
+		unit, err := c.GetSandboxAllocationUnit(ctx, unitId)
+		if err != nil {
+			return err
+		}
+		if unit.Locked {
+			return &Error{ResourceName: "sandbox cleanup request", Identifier: fmt.Sprintf("sandbox allocation unit %d", unitId), Err: ErrSandboxLocked}
+		}
+	}
+
+	return c.createSandboxCleanupRequest(ctx, unitId)
+}
+
 // PollRequestFinished periodically checks whether the specified request on given allocation unit has finished.
 // The `requestType` should be one of `allocation` or `cleanup`. The check is done once every `pollTime` elapses.
 func (c *Client) PollRequestFinished(ctx context.Context, unitId int64, pollTime time.Duration, requestType string) (*SandboxRequest, error) {
@@ -195,12 +264,23 @@ func (c *Client) PollRequestFinished(ctx context.Context, unitId int64, pollTime
 
 // CreateSandboxCleanupRequestAwait starts the cleanup request for the given sandbox allocation unit and waits until it finishes.
 // Once the cleanup is started, the status is checked once every `pollTime` elapses.
+// The cleanup of a locked unit is refused like in CreateSandboxCleanupRequest.
 func (c *Client) CreateSandboxCleanupRequestAwait(ctx context.Context, unitId int64, pollTime time.Duration) error {
-	err := c.CreateSandboxCleanupRequest(ctx, unitId)
+	return c.CreateSandboxCleanupRequestWithOptionsAwait(ctx, unitId, pollTime, SandboxCleanupOptions{})
+}
+
+// CreateSandboxCleanupRequestWithOptionsAwait starts the cleanup request for the given sandbox allocation unit like
+// CreateSandboxCleanupRequestWithOptions and waits until it finishes like CreateSandboxCleanupRequestAwait.
+func (c *Client) CreateSandboxCleanupRequestWithOptionsAwait(ctx context.Context, unitId int64, pollTime time.Duration, options SandboxCleanupOptions) error {
+	err := c.CreateSandboxCleanupRequestWithOptions(ctx, unitId, options)
 	if err != nil {
 		return err
 	}
 
+	return c.awaitSandboxCleanupRequest(ctx, unitId, pollTime)
+}
+
+func (c *Client) awaitSandboxCleanupRequest(ctx context.Context, unitId int64, pollTime time.Duration) error {
 	cleanupRequest, err := c.PollRequestFinished(ctx, unitId, pollTime, "cleanup")
 	// After cleanup is finished it deletes itself and 404 is thrown
 	if errors.Is(err, ErrNotFound) {
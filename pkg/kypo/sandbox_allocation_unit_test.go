@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -246,6 +247,44 @@ func TestCreateSandboxAllocationUnitServerError(t *testing.T) {
 	assert.Equal(t, expected, err)
 }
 
+func TestCreateSandboxAllocationUnitsWithVariablesSuccessful(t *testing.T) {
+	counter := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		counter++
+		switch counter {
+		case 1:
+			assertSandboxPoolGet(t, request)
+
+			response, _ := json.Marshal(sandboxPoolResponse)
+			_, _ = fmt.Fprint(writer, string(response))
+		case 2:
+			assertSandboxDefinitionVariablesGet(t, request)
+
+			response, _ := json.Marshal(sandboxDefinitionVariablesResponse)
+			_, _ = fmt.Fprint(writer, string(response))
+		default:
+			assertSandboxAllocationUnitCreate(t, request)
+			body, err := io.ReadAll(request.Body)
+			assert.NoError(t, err)
+			assert.JSONEq(t, `{"variables": {"flag": "secret"}}`, string(body))
+
+			response, _ := json.Marshal(sandboxAllocationUnitResponsePagination)
+			_, _ = fmt.Fprint(writer, string(response))
+		}
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := []kypo.SandboxAllocationUnit{expectedSandboxAllocationUnit}
+
+	actual, err := c.CreateSandboxAllocationUnitsWithVariables(context.Background(), 1, 1, map[string]string{"flag": "secret"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+	assert.Equal(t, 3, counter)
+}
+
 func assertSandboxRequest(t *testing.T, request *http.Request, requestType string) {
 	assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
 	assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
@@ -344,8 +383,22 @@ func assertSandboxAllocationUnitCleanup(t *testing.T, request *http.Request) {
 	assert.Equal(t, http.MethodPost, request.Method)
 }
 
+// serveUnlockedSandboxAllocationUnit answers the lock check of a cleanup request with an unlocked unit.
+func serveUnlockedSandboxAllocationUnit(t *testing.T, writer http.ResponseWriter, request *http.Request) bool {
+	if request.Method != http.MethodGet || request.URL.Path != "/kypo-sandbox-service/api/v1/sandbox-allocation-units/1" {
+		return false
+	}
+	assertSandboxAllocationUnitGet(t, request)
+	response, _ := json.Marshal(sandboxAllocationUnitResponse)
+	_, _ = fmt.Fprint(writer, string(response))
+	return true
+}
+
 func TestCleanupSandboxAllocationUnitSuccessful(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if serveUnlockedSandboxAllocationUnit(t, writer, request) {
+			return
+		}
 		assertSandboxAllocationUnitCleanup(t, request)
 
 		writer.WriteHeader(http.StatusCreated)
@@ -361,6 +414,9 @@ func TestCleanupSandboxAllocationUnitSuccessful(t *testing.T) {
 
 func TestCleanupSandboxAllocationUnitNotFound(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if serveUnlockedSandboxAllocationUnit(t, writer, request) {
+			return
+		}
 		assertSandboxAllocationUnitCleanup(t, request)
 
 		writer.WriteHeader(http.StatusNotFound)
@@ -388,6 +444,9 @@ func TestCleanupSandboxAllocationUnitNotFound(t *testing.T) {
 
 func TestCleanupSandboxAllocationUnitServerError(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if serveUnlockedSandboxAllocationUnit(t, writer, request) {
+			return
+		}
 		assertSandboxAllocationUnitCleanup(t, request)
 
 		writer.WriteHeader(http.StatusInternalServerError)
@@ -405,9 +464,109 @@ func TestCleanupSandboxAllocationUnitServerError(t *testing.T) {
 	assert.Equal(t, expected, err)
 }
 
+func TestCleanupSandboxAllocationUnitLocked(t *testing.T) {
+	cleanups := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method == http.MethodPost {
+			cleanups++
+			writer.WriteHeader(http.StatusCreated)
+			return
+		}
+		assertSandboxAllocationUnitGet(t, request)
+
+		unit := sandboxAllocationUnitResponse
+		unit.Locked = true
+		response, _ := json.Marshal(unit)
+		_, _ = fmt.Fprint(writer, string(response))
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	err := c.CreateSandboxCleanupRequest(context.Background(), 1)
+	assert.ErrorIs(t, err, kypo.ErrSandboxLocked)
+
+	err = c.CreateSandboxCleanupRequestAwait(context.Background(), 1, 1)
+	assert.ErrorIs(t, err, kypo.ErrSandboxLocked)
+
+	assert.Equal(t, 0, cleanups)
+}
+
+func TestCleanupSandboxAllocationUnitWithOptionsNotLocked(t *testing.T) {
+	counter := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		counter++
+		if counter == 1 {
+			assertSandboxAllocationUnitGet(t, request)
+
+			response, _ := json.Marshal(sandboxAllocationUnitResponse)
+			_, _ = fmt.Fprint(writer, string(response))
+			return
+		}
+
+		assertSandboxAllocationUnitCleanup(t, request)
+		writer.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	err := c.CreateSandboxCleanupRequestWithOptions(context.Background(), 1, kypo.SandboxCleanupOptions{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, counter)
+}
+
+func TestCleanupSandboxAllocationUnitWithOptionsLocked(t *testing.T) {
+	counter := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		counter++
+		assertSandboxAllocationUnitGet(t, request)
+
+		unit := sandboxAllocationUnitResponse
+		unit.Locked = true
+		response, _ := json.Marshal(unit)
+		_, _ = fmt.Fprint(writer, string(response))
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+	expected := &kypo.Error{
+		ResourceName: "sandbox cleanup request",
+		Identifier:   "sandbox allocation unit 1",
+		Err:          kypo.ErrSandboxLocked,
+	}
+
+	err := c.CreateSandboxCleanupRequestWithOptions(context.Background(), 1, kypo.SandboxCleanupOptions{})
+
+	assert.Equal(t, expected, err)
+	assert.ErrorIs(t, err, kypo.ErrSandboxLocked)
+	assert.Equal(t, 1, counter)
+}
+
+func TestCleanupSandboxAllocationUnitWithOptionsIgnoreLock(t *testing.T) {
+	counter := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		counter++
+		assertSandboxAllocationUnitCleanup(t, request)
+		writer.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	err := c.CreateSandboxCleanupRequestWithOptions(context.Background(), 1, kypo.SandboxCleanupOptions{IgnoreLock: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, counter)
+}
+
 func TestCleanupSandboxAllocationUnitAwaitSuccessful(t *testing.T) {
 	counter := 0
 	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if serveUnlockedSandboxAllocationUnit(t, writer, request) {
+			return
+		}
 		counter++
 		if counter == 1 {
 			assertSandboxAllocationUnitCleanup(t, request)
@@ -445,6 +604,9 @@ func TestCleanupSandboxAllocationUnitAwaitSuccessfulWithDelay(t *testing.T) {
 		Stages:           []string{"IN_QUEUE", "IN_QUEUE", "IN_QUEUE"},
 	}
 	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if serveUnlockedSandboxAllocationUnit(t, writer, request) {
+			return
+		}
 		counter++
 		if counter == 1 {
 			assertSandboxAllocationUnitCleanup(t, request)
@@ -480,6 +642,9 @@ func TestCleanupSandboxAllocationUnitAwaitSuccessfulWithDelay(t *testing.T) {
 func TestCleanupSandboxAllocationUnitAwaitFailed(t *testing.T) {
 	counter := 0
 	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if serveUnlockedSandboxAllocationUnit(t, writer, request) {
+			return
+		}
 		counter++
 		if counter == 1 {
 			assertSandboxAllocationUnitCleanup(t, request)
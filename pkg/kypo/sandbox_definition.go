@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 )
 
@@ -21,6 +22,65 @@ type sandboxDefinitionRequest struct {
 	Rev string `json:"rev"`
 }
 
+type SandboxDefinitionVariable struct {
+	Name        string `json:"name" tfsdk:"name"`
+	Type        string `json:"type" tfsdk:"type"`
+	Description string `json:"description" tfsdk:"description"`
+	Required    bool   `json:"required" tfsdk:"required"`
+	Default     string `json:"default" tfsdk:"default"`
+}
+
+type Topology struct {
+	Hosts    []TopologyHost   `json:"hosts" tfsdk:"hosts"`
+	Routers  []TopologyRouter `json:"routers" tfsdk:"routers"`
+	Switches []TopologySwitch `json:"switches" tfsdk:"switches"`
+	Links    []TopologyLink   `json:"links" tfsdk:"links"`
+	Ports    []TopologyPort   `json:"ports" tfsdk:"ports"`
+}
+
+type TopologyHost struct {
+	Name       string   `json:"name" tfsdk:"name"`
+	OsType     string   `json:"os_type" tfsdk:"os_type"`
+	GuiAccess  bool     `json:"gui_access" tfsdk:"gui_access"`
+	Hidden     bool     `json:"hidden" tfsdk:"hidden"`
+	Containers []string `json:"containers" tfsdk:"containers"`
+}
+
+type TopologyRouter struct {
+	Name      string `json:"name" tfsdk:"name"`
+	OsType    string `json:"os_type" tfsdk:"os_type"`
+	GuiAccess bool   `json:"gui_access" tfsdk:"gui_access"`
+}
+
+type TopologySwitch struct {
+	Name   string `json:"name" tfsdk:"name"`
+	Cidr   string `json:"cidr" tfsdk:"cidr"`
+	Hidden bool   `json:"hidden" tfsdk:"hidden"`
+}
+
+type TopologyLink struct {
+	PortA string `json:"port_a" tfsdk:"port_a"`
+	PortB string `json:"port_b" tfsdk:"port_b"`
+}
+
+type TopologyPort struct {
+	Ip     string `json:"ip" tfsdk:"ip"`
+	Mac    string `json:"mac" tfsdk:"mac"`
+	Parent string `json:"parent" tfsdk:"parent"`
+	Name   string `json:"name" tfsdk:"name"`
+}
+
+// HostIp returns the IP address of the first port of the host or router with the given name.
+// The second return value is false if no such port exists.
+func (t *Topology) HostIp(name string) (string, bool) {
+	for _, port := range t.Ports {
+		if port.Parent == name {
+			return port.Ip, true
+		}
+	}
+	return "", false
+}
+
 // GetSandboxDefinition reads the given sandbox definition.
 func (c *Client) GetSandboxDefinition(ctx context.Context, definitionID int64) (*SandboxDefinition, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/kypo-sandbox-service/api/v1/definitions/%d", c.Endpoint, definitionID), nil)
@@ -42,6 +102,12 @@ func (c *Client) GetSandboxDefinition(ctx context.Context, definitionID int64) (
 	return &definition, nil
 }
 
+// ListSandboxDefinitions reads all sandbox definitions from all pages.
+func (c *Client) ListSandboxDefinitions(ctx context.Context) ([]SandboxDefinition, error) {
+	return getAllSandboxPages[SandboxDefinition](ctx, c, fmt.Sprintf("%s/kypo-sandbox-service/api/v1/definitions", c.Endpoint),
+		"sandbox definitions", "")
+}
+
 // CreateSandboxDefinition creates a sandbox definition.
 // The `url` must be a URL to a GitLab repository where the sandbox definition is hosted.
 // The `rev` specifies the Git revision to be used.
@@ -84,3 +150,59 @@ func (c *Client) DeleteSandboxDefinition(ctx context.Context, definitionID int64
 
 	return nil
 }
+
+// GetSandboxDefinitionTopology reads the topology of the given sandbox definition.
+func (c *Client) GetSandboxDefinitionTopology(ctx context.Context, definitionID int64) (*Topology, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/kypo-sandbox-service/api/v1/definitions/%d/topology", c.Endpoint, definitionID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, _, err := c.doRequestWithRetry(req, http.StatusOK, "sandbox definition topology", definitionID)
+	if err != nil {
+		return nil, err
+	}
+
+	topology := Topology{}
+	err = json.Unmarshal(body, &topology)
+	if err != nil {
+		return nil, err
+	}
+
+	return &topology, nil
+}
+
+// GetSandboxDefinitionVariables reads the variables declared by the given sandbox definition from all pages.
+// Values of the variables can be passed to CreateSandboxPoolWithVariables or CreateSandboxAllocationUnitsWithVariables.
+func (c *Client) GetSandboxDefinitionVariables(ctx context.Context, definitionID int64) ([]SandboxDefinitionVariable, error) {
+	return getAllSandboxPages[SandboxDefinitionVariable](ctx, c, fmt.Sprintf("%s/kypo-sandbox-service/api/v1/definitions/%d/variables", c.Endpoint, definitionID),
+		"sandbox definition variables", definitionID)
+}
+
+// ValidateSandboxDefinitionVariables checks that `values` contain a value for every required variable
+// without a default value and that `values` do not contain variables which are not declared.
+func ValidateSandboxDefinitionVariables(variables []SandboxDefinitionVariable, values map[string]string) error {
+	declared := map[string]bool{}
+	var missing, unknown []string
+	for _, variable := range variables {
+		declared[variable.Name] = true
+		if _, ok := values[variable.Name]; !ok && variable.Required && variable.Default == "" {
+			missing = append(missing, variable.Name)
+		}
+	}
+	for name := range values {
+		if !declared[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(unknown)
+
+	if len(missing) != 0 {
+		return fmt.Errorf("missing values of required variables: %s", strings.Join(missing, ", "))
+	}
+	if len(unknown) != 0 {
+		return fmt.Errorf("values of undeclared variables: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
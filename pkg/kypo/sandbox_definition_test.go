@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -195,6 +196,34 @@ func TestCreateSandboxDefinitionServerError(t *testing.T) {
 	assert.Equal(t, expected, err)
 }
 
+func TestCreateSandboxDefinitionRetryResendsBody(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertSandboxDefinitionCreate(t, request)
+		body, _ := io.ReadAll(request.Body)
+		assert.JSONEq(t, `{"url": "url", "rev": "rev"}`, string(body))
+
+		attempts++
+		if attempts == 1 {
+			writer.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		writer.WriteHeader(http.StatusCreated)
+		response, _ := json.Marshal(sandboxDefinitionResponse)
+		_, _ = fmt.Fprint(writer, string(response))
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+	c.RetryCount = 1
+
+	actual, err := c.CreateSandboxDefinition(context.Background(), "url", "rev")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), actual.Id)
+	assert.Equal(t, 2, attempts)
+}
+
 func assertSandboxDefinitionDelete(t *testing.T, request *http.Request) {
 	assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
 	assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
@@ -262,3 +291,121 @@ func TestDeleteSandboxDefinitionServerError(t *testing.T) {
 
 	assert.Equal(t, expected, actual)
 }
+
+func TestGetSandboxDefinitionTopologySuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+		assert.Equal(t, "/kypo-sandbox-service/api/v1/definitions/1/topology", request.URL.Path)
+		assert.Equal(t, http.MethodGet, request.Method)
+
+		_, _ = fmt.Fprint(writer, topologyResponse)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.GetSandboxDefinitionTopology(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expectedTopology, actual)
+}
+
+type SandboxDefinitionVariable struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+	Default     string `json:"default"`
+}
+
+var sandboxDefinitionVariablesResponse = Pagination{
+	Page:       1,
+	PageSize:   50,
+	PageCount:  1,
+	Count:      2,
+	TotalCount: 2,
+	Results: []SandboxDefinitionVariable{
+		{Name: "flag", Type: "text", Description: "flag placed on the server", Required: true},
+		{Name: "user", Type: "username", Required: true, Default: "debian"},
+	},
+}
+
+func assertSandboxDefinitionVariablesGet(t *testing.T, request *http.Request) {
+	assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
+	assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+	assert.Equal(t, "/kypo-sandbox-service/api/v1/definitions/1/variables", request.URL.Path)
+	assert.Equal(t, http.MethodGet, request.Method)
+}
+
+func TestGetSandboxDefinitionVariablesSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertSandboxDefinitionVariablesGet(t, request)
+
+		response, _ := json.Marshal(sandboxDefinitionVariablesResponse)
+		_, _ = fmt.Fprint(writer, string(response))
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := []kypo.SandboxDefinitionVariable{
+		{Name: "flag", Type: "text", Description: "flag placed on the server", Required: true},
+		{Name: "user", Type: "username", Required: true, Default: "debian"},
+	}
+
+	actual, err := c.GetSandboxDefinitionVariables(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestGetSandboxDefinitionVariablesAllPages(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertSandboxDefinitionVariablesGet(t, request)
+
+		r := Pagination{
+			Page:       1,
+			PageSize:   100,
+			PageCount:  2,
+			Count:      1,
+			TotalCount: 2,
+			Results:    []SandboxDefinitionVariable{{Name: "flag", Type: "text"}},
+		}
+		if request.URL.Query().Get("page") == "2" {
+			r.Page = 2
+			r.Results = []SandboxDefinitionVariable{{Name: "user", Type: "username"}}
+		}
+		response, _ := json.Marshal(r)
+		_, _ = fmt.Fprint(writer, string(response))
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := []kypo.SandboxDefinitionVariable{{Name: "flag", Type: "text"}, {Name: "user", Type: "username"}}
+
+	actual, err := c.GetSandboxDefinitionVariables(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestValidateSandboxDefinitionVariables(t *testing.T) {
+	variables := []kypo.SandboxDefinitionVariable{
+		{Name: "flag", Required: true},
+		{Name: "user", Required: true, Default: "debian"},
+		{Name: "port"},
+	}
+
+	assert.NoError(t, kypo.ValidateSandboxDefinitionVariables(variables, map[string]string{"flag": "secret"}))
+	assert.NoError(t, kypo.ValidateSandboxDefinitionVariables(variables, map[string]string{"flag": "secret", "user": "root", "port": "22"}))
+	assert.EqualError(t, kypo.ValidateSandboxDefinitionVariables(variables, nil),
+		"missing values of required variables: flag")
+	assert.EqualError(t, kypo.ValidateSandboxDefinitionVariables(variables, map[string]string{"flag": "secret", "usr": "root"}),
+		"values of undeclared variables: usr")
+
+	assert.EqualError(t, kypo.ValidateSandboxDefinitionVariables(
+		[]kypo.SandboxDefinitionVariable{{Name: "user", Required: true}, {Name: "flag", Required: true}}, nil),
+		"missing values of required variables: flag, user")
+}
@@ -21,8 +21,9 @@ type SandboxPool struct {
 }
 
 type sandboxPoolRequest struct {
-	DefinitionId int64 `json:"definition_id"`
-	MaxSize      int64 `json:"max_size"`
+	DefinitionId int64             `json:"definition_id"`
+	MaxSize      int64             `json:"max_size"`
+	Variables    map[string]string `json:"variables,omitempty"`
 }
 
 type HardwareUsage struct {
@@ -55,9 +56,34 @@ func (c *Client) GetSandboxPool(ctx context.Context, poolId int64) (*SandboxPool
 	return &pool, nil
 }
 
+// ListSandboxPools reads all sandbox pools from all pages.
+func (c *Client) ListSandboxPools(ctx context.Context) ([]SandboxPool, error) {
+	return getAllSandboxPages[SandboxPool](ctx, c, fmt.Sprintf("%s/kypo-sandbox-service/api/v1/pools", c.Endpoint), "sandbox pools", "")
+}
+
 // CreateSandboxPool creates a sandbox pool from given sandbox definition id and the maximum size of the pool.
 func (c *Client) CreateSandboxPool(ctx context.Context, definitionId, maxSize int64) (*SandboxPool, error) {
-	requestBody, err := json.Marshal(sandboxPoolRequest{definitionId, maxSize})
+	return c.createSandboxPool(ctx, sandboxPoolRequest{DefinitionId: definitionId, MaxSize: maxSize})
+}
+
+// CreateSandboxPoolWithVariables creates a sandbox pool like CreateSandboxPool and passes the values of sandbox
+// definition variables to it. The values are validated against the variables declared by the sandbox definition.
+func (c *Client) CreateSandboxPoolWithVariables(ctx context.Context, definitionId, maxSize int64, variables map[string]string) (*SandboxPool, error) {
+	declared, err := c.GetSandboxDefinitionVariables(ctx, definitionId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ValidateSandboxDefinitionVariables(declared, variables)
+	if err != nil {
+		return nil, &Error{ResourceName: "sandbox pool", Identifier: fmt.Sprintf("sandbox definition %d", definitionId), Err: err}
+	}
+
+	return c.createSandboxPool(ctx, sandboxPoolRequest{DefinitionId: definitionId, MaxSize: maxSize, Variables: variables})
+}
+
+func (c *Client) createSandboxPool(ctx context.Context, request sandboxPoolRequest) (*SandboxPool, error) {
+	requestBody, err := json.Marshal(request)
 	if err != nil {
 		return nil, err
 	}
@@ -67,7 +93,7 @@ func (c *Client) CreateSandboxPool(ctx context.Context, definitionId, maxSize in
 		return nil, err
 	}
 
-	body, _, err := c.doRequestWithRetry(req, http.StatusCreated, "sandbox pool", fmt.Sprintf("sandbox definition %d", definitionId))
+	body, _, err := c.doRequestWithRetry(req, http.StatusCreated, "sandbox pool", fmt.Sprintf("sandbox definition %d", request.DefinitionId))
 	if err != nil {
 		return nil, err
 	}
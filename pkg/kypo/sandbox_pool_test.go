@@ -425,3 +425,62 @@ func TestCleanupSandboxPoolServerError(t *testing.T) {
 
 	assert.Equal(t, expected, actual)
 }
+
+func TestCreateSandboxPoolWithVariablesSuccessful(t *testing.T) {
+	counter := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		counter++
+		if counter == 1 {
+			assertSandboxDefinitionVariablesGet(t, request)
+
+			response, _ := json.Marshal(sandboxDefinitionVariablesResponse)
+			_, _ = fmt.Fprint(writer, string(response))
+			return
+		}
+
+		assert.Equal(t, "/kypo-sandbox-service/api/v1/pools", request.URL.Path)
+		assert.Equal(t, http.MethodPost, request.Method)
+		body, err := io.ReadAll(request.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"definition_id": 1, "max_size": 1, "variables": {"flag": "secret"}}`, string(body))
+
+		writer.WriteHeader(http.StatusCreated)
+		response, _ := json.Marshal(sandboxPoolResponse)
+		_, _ = fmt.Fprint(writer, string(response))
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.CreateSandboxPoolWithVariables(context.Background(), 1, 1, map[string]string{"flag": "secret"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expectedPoolResponse, actual)
+	assert.Equal(t, 2, counter)
+}
+
+func TestCreateSandboxPoolWithVariablesMissing(t *testing.T) {
+	counter := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		counter++
+		assertSandboxDefinitionVariablesGet(t, request)
+
+		response, _ := json.Marshal(sandboxDefinitionVariablesResponse)
+		_, _ = fmt.Fprint(writer, string(response))
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.Error{
+		ResourceName: "sandbox pool",
+		Identifier:   "sandbox definition 1",
+		Err:          fmt.Errorf("missing values of required variables: flag"),
+	}
+
+	actual, err := c.CreateSandboxPoolWithVariables(context.Background(), 1, 1, map[string]string{})
+
+	assert.Nil(t, actual)
+	assert.Equal(t, expected, err)
+	assert.Equal(t, 1, counter)
+}
@@ -0,0 +1,327 @@
+package kypo_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type Sandbox struct {
+	Id               string `json:"id"`
+	LockId           *int   `json:"lock_id"`
+	AllocationUnitId int    `json:"allocation_unit_id"`
+}
+
+var (
+	lockId          = 1
+	sandboxResponse = Sandbox{
+		Id:               "d5b1a4f4-0c8b-4b0a-9f3c-3c1b2a1d5e6f",
+		LockId:           &lockId,
+		AllocationUnitId: 1,
+	}
+	expectedSandbox = kypo.Sandbox{
+		Id:               "d5b1a4f4-0c8b-4b0a-9f3c-3c1b2a1d5e6f",
+		LockId:           1,
+		AllocationUnitId: 1,
+	}
+	topologyResponse = `{
+		"hosts": [{"name": "server", "os_type": "linux", "gui_access": false, "hidden": false, "containers": []}],
+		"routers": [{"name": "router", "os_type": "linux", "gui_access": false}],
+		"switches": [{"name": "server-switch", "cidr": "10.10.20.0/24", "hidden": false}],
+		"links": [{"port_a": "server-port", "port_b": "router-port"}],
+		"ports": [
+			{"ip": "10.10.20.5", "mac": "fa:16:3e:00:00:01", "parent": "server", "name": "server-port"},
+			{"ip": "10.10.20.1", "mac": "fa:16:3e:00:00:02", "parent": "router", "name": "router-port"}
+		]
+	}`
+	expectedTopology = kypo.Topology{
+		Hosts:    []kypo.TopologyHost{{Name: "server", OsType: "linux", Containers: []string{}}},
+		Routers:  []kypo.TopologyRouter{{Name: "router", OsType: "linux"}},
+		Switches: []kypo.TopologySwitch{{Name: "server-switch", Cidr: "10.10.20.0/24"}},
+		Links:    []kypo.TopologyLink{{PortA: "server-port", PortB: "router-port"}},
+		Ports: []kypo.TopologyPort{
+			{Ip: "10.10.20.5", Mac: "fa:16:3e:00:00:01", Parent: "server", Name: "server-port"},
+			{Ip: "10.10.20.1", Mac: "fa:16:3e:00:00:02", Parent: "router", Name: "router-port"},
+		},
+	}
+)
+
+func assertSandboxGet(t *testing.T, request *http.Request, path string) {
+	assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
+	assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+	assert.Equal(t, path, request.URL.Path)
+	assert.Equal(t, http.MethodGet, request.Method)
+}
+
+func TestGetSandboxSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertSandboxGet(t, request, "/kypo-sandbox-service/api/v1/sandboxes/d5b1a4f4-0c8b-4b0a-9f3c-3c1b2a1d5e6f")
+
+		response, _ := json.Marshal(sandboxResponse)
+		_, _ = fmt.Fprint(writer, string(response))
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.GetSandbox(context.Background(), "d5b1a4f4-0c8b-4b0a-9f3c-3c1b2a1d5e6f")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expectedSandbox, actual)
+}
+
+func TestGetSandboxNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertSandboxGet(t, request, "/kypo-sandbox-service/api/v1/sandboxes/d5b1a4f4-0c8b-4b0a-9f3c-3c1b2a1d5e6f")
+
+		writer.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.Error{
+		ResourceName: "sandbox",
+		Identifier:   "d5b1a4f4-0c8b-4b0a-9f3c-3c1b2a1d5e6f",
+		Err:          kypo.ErrNotFound,
+	}
+
+	sandbox, actual := c.GetSandbox(context.Background(), "d5b1a4f4-0c8b-4b0a-9f3c-3c1b2a1d5e6f")
+
+	assert.Nil(t, sandbox)
+	assert.Equal(t, expected, actual)
+}
+
+func TestGetSandboxByAllocationUnitSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertSandboxGet(t, request, "/kypo-sandbox-service/api/v1/sandbox-allocation-units/1/sandbox")
+
+		response, _ := json.Marshal(sandboxResponse)
+		_, _ = fmt.Fprint(writer, string(response))
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.GetSandboxByAllocationUnit(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expectedSandbox, actual)
+}
+
+func TestGetSandboxByAllocationUnitServerError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertSandboxGet(t, request, "/kypo-sandbox-service/api/v1/sandbox-allocation-units/1/sandbox")
+
+		writer.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.Error{
+		ResourceName: "sandbox",
+		Identifier:   "sandbox allocation unit 1",
+		Err:          fmt.Errorf("status: 500, body: "),
+	}
+
+	sandbox, actual := c.GetSandboxByAllocationUnit(context.Background(), 1)
+
+	assert.Nil(t, sandbox)
+	assert.Equal(t, expected, actual)
+}
+
+func TestGetSandboxTopologySuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertSandboxGet(t, request, "/kypo-sandbox-service/api/v1/sandboxes/d5b1a4f4-0c8b-4b0a-9f3c-3c1b2a1d5e6f/topology")
+
+		_, _ = fmt.Fprint(writer, topologyResponse)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.GetSandboxTopology(context.Background(), "d5b1a4f4-0c8b-4b0a-9f3c-3c1b2a1d5e6f")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expectedTopology, actual)
+
+	ip, ok := actual.HostIp("server")
+	assert.True(t, ok)
+	assert.Equal(t, "10.10.20.5", ip)
+
+	_, ok = actual.HostIp("attacker")
+	assert.False(t, ok)
+}
+
+func TestGetSandboxTopologyNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertSandboxGet(t, request, "/kypo-sandbox-service/api/v1/sandboxes/d5b1a4f4-0c8b-4b0a-9f3c-3c1b2a1d5e6f/topology")
+
+		writer.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.Error{
+		ResourceName: "sandbox topology",
+		Identifier:   "d5b1a4f4-0c8b-4b0a-9f3c-3c1b2a1d5e6f",
+		Err:          kypo.ErrNotFound,
+	}
+
+	topology, actual := c.GetSandboxTopology(context.Background(), "d5b1a4f4-0c8b-4b0a-9f3c-3c1b2a1d5e6f")
+
+	assert.Nil(t, topology)
+	assert.Equal(t, expected, actual)
+}
+
+func assertSandboxLock(t *testing.T, request *http.Request, method string) {
+	assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
+	assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+	assert.Equal(t, "/kypo-sandbox-service/api/v1/sandboxes/d5b1a4f4-0c8b-4b0a-9f3c-3c1b2a1d5e6f/lock", request.URL.Path)
+	assert.Equal(t, method, request.Method)
+}
+
+func TestLockSandboxSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertSandboxLock(t, request, http.MethodPost)
+
+		writer.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprint(writer, `{"id": 1, "sandbox_id": "d5b1a4f4-0c8b-4b0a-9f3c-3c1b2a1d5e6f"}`)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := kypo.SandboxLock{
+		Id:        1,
+		SandboxId: "d5b1a4f4-0c8b-4b0a-9f3c-3c1b2a1d5e6f",
+	}
+
+	actual, err := c.LockSandbox(context.Background(), "d5b1a4f4-0c8b-4b0a-9f3c-3c1b2a1d5e6f")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expected, actual)
+}
+
+func TestLockSandboxServerError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertSandboxLock(t, request, http.MethodPost)
+
+		writer.WriteHeader(http.StatusBadRequest)
+		_, _ = fmt.Fprint(writer, `{"detail": "Sandbox is already locked."}`)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.Error{
+		ResourceName: "sandbox lock",
+		Identifier:   "d5b1a4f4-0c8b-4b0a-9f3c-3c1b2a1d5e6f",
+		Err:          fmt.Errorf(`status: 400, body: {"detail": "Sandbox is already locked."}`),
+	}
+
+	lock, err := c.LockSandbox(context.Background(), "d5b1a4f4-0c8b-4b0a-9f3c-3c1b2a1d5e6f")
+
+	assert.Nil(t, lock)
+	assert.Equal(t, expected, err)
+}
+
+func TestUnlockSandboxSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertSandboxLock(t, request, http.MethodDelete)
+
+		writer.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	err := c.UnlockSandbox(context.Background(), "d5b1a4f4-0c8b-4b0a-9f3c-3c1b2a1d5e6f")
+
+	assert.NoError(t, err)
+}
+
+func TestUnlockSandboxNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertSandboxLock(t, request, http.MethodDelete)
+
+		writer.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.Error{
+		ResourceName: "sandbox lock",
+		Identifier:   "d5b1a4f4-0c8b-4b0a-9f3c-3c1b2a1d5e6f",
+		Err:          kypo.ErrNotFound,
+	}
+
+	err := c.UnlockSandbox(context.Background(), "d5b1a4f4-0c8b-4b0a-9f3c-3c1b2a1d5e6f")
+
+	assert.Equal(t, expected, err)
+}
+
+func TestListLockedSandboxesSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertSandboxGet(t, request, "/kypo-sandbox-service/api/v1/pools/1/sandboxes")
+
+		r := Pagination{
+			Page:       1,
+			PageSize:   50,
+			PageCount:  1,
+			Count:      2,
+			TotalCount: 2,
+			Results: []Sandbox{
+				sandboxResponse,
+				{Id: "0a6e1f0e-5b0f-4b8e-8f5d-1c2b3a4d5e6f", LockId: nil, AllocationUnitId: 2},
+			},
+		}
+		response, _ := json.Marshal(r)
+		_, _ = fmt.Fprint(writer, string(response))
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.ListLockedSandboxes(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []kypo.Sandbox{expectedSandbox}, actual)
+}
+
+func TestListLockedSandboxesAllPages(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertSandboxGet(t, request, "/kypo-sandbox-service/api/v1/pools/1/sandboxes")
+
+		r := Pagination{
+			Page:       1,
+			PageSize:   100,
+			PageCount:  2,
+			Count:      1,
+			TotalCount: 2,
+			Results:    []Sandbox{{Id: "0a6e1f0e-5b0f-4b8e-8f5d-1c2b3a4d5e6f", LockId: nil, AllocationUnitId: 2}},
+		}
+		if request.URL.Query().Get("page") == "2" {
+			r.Page = 2
+			r.Results = []Sandbox{sandboxResponse}
+		}
+		response, _ := json.Marshal(r)
+		_, _ = fmt.Fprint(writer, string(response))
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.ListLockedSandboxes(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []kypo.Sandbox{expectedSandbox}, actual)
+}
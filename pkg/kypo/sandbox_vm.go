@@ -0,0 +1,84 @@
+package kypo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type VirtualMachine struct {
+	Name       string `json:"name" tfsdk:"name"`
+	Status     string `json:"status" tfsdk:"status"`
+	ImageId    string `json:"image_id" tfsdk:"image_id"`
+	FlavorName string `json:"flavor_name" tfsdk:"flavor_name"`
+	Created    string `json:"created" tfsdk:"created"`
+}
+
+// VMAction is an action which can be performed on a virtual machine of a sandbox.
+type VMAction string
+
+const (
+	VMActionResume  VMAction = "resume"
+	VMActionReboot  VMAction = "reboot"
+	VMActionSuspend VMAction = "suspend"
+)
+
+type vmActionRequest struct {
+	Action VMAction `json:"action"`
+}
+
+// GetSandboxVMs reads the virtual machines of the given sandbox from all pages.
+func (c *Client) GetSandboxVMs(ctx context.Context, sandboxId string) ([]VirtualMachine, error) {
+	return getAllSandboxPages[VirtualMachine](ctx, c, fmt.Sprintf("%s/kypo-sandbox-service/api/v1/sandboxes/%s/vms", c.Endpoint, sandboxId),
+		"sandbox virtual machines", sandboxId)
+}
+
+// PerformVMAction performs the `action` on the virtual machine `vmName` of the given sandbox.
+func (c *Client) PerformVMAction(ctx context.Context, sandboxId, vmName string, action VMAction) error {
+	switch action {
+	case VMActionResume, VMActionReboot, VMActionSuspend:
+	default:
+		return fmt.Errorf("unknown virtual machine action %q", action)
+	}
+
+	requestBody, err := json.Marshal(vmActionRequest{action})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, fmt.Sprintf("%s/kypo-sandbox-service/api/v1/sandboxes/%s/vms/%s",
+		c.Endpoint, sandboxId, url.PathEscape(vmName)), strings.NewReader(string(requestBody)))
+	if err != nil {
+		return err
+	}
+
+	_, _, err = c.doRequestWithRetry(req, http.StatusOK, "sandbox virtual machine", fmt.Sprintf("%s in sandbox %s", vmName, sandboxId))
+	return err
+}
+
+// GetVMConsole returns the URL of the console of the virtual machine `vmName` of the given sandbox.
+func (c *Client) GetVMConsole(ctx context.Context, sandboxId, vmName string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/kypo-sandbox-service/api/v1/sandboxes/%s/vms/%s/console",
+		c.Endpoint, sandboxId, url.PathEscape(vmName)), nil)
+	if err != nil {
+		return "", err
+	}
+
+	body, _, err := c.doRequestWithRetry(req, http.StatusOK, "sandbox virtual machine console", fmt.Sprintf("%s in sandbox %s", vmName, sandboxId))
+	if err != nil {
+		return "", err
+	}
+
+	console := struct {
+		Url string `json:"url"`
+	}{}
+	err = json.Unmarshal(body, &console)
+	if err != nil {
+		return "", err
+	}
+
+	return console.Url, nil
+}
@@ -0,0 +1,161 @@
+package kypo_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type VirtualMachine struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	ImageId    string `json:"image_id"`
+	FlavorName string `json:"flavor_name"`
+	Created    string `json:"created"`
+}
+
+func TestGetSandboxVMsSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+		assert.Equal(t, "/kypo-sandbox-service/api/v1/sandboxes/1/vms", request.URL.Path)
+		assert.Equal(t, http.MethodGet, request.Method)
+
+		r := Pagination{
+			Page:       1,
+			PageSize:   50,
+			PageCount:  1,
+			Count:      1,
+			TotalCount: 1,
+			Results: []VirtualMachine{{
+				Name:       "server",
+				Status:     "ACTIVE",
+				ImageId:    "image",
+				FlavorName: "standard.small",
+				Created:    "2023-11-26T17:04:20.032500+01:00",
+			}},
+		}
+		response, _ := json.Marshal(r)
+		_, _ = fmt.Fprint(writer, string(response))
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := []kypo.VirtualMachine{{
+		Name:       "server",
+		Status:     "ACTIVE",
+		ImageId:    "image",
+		FlavorName: "standard.small",
+		Created:    "2023-11-26T17:04:20.032500+01:00",
+	}}
+
+	actual, err := c.GetSandboxVMs(context.Background(), "1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestGetSandboxVMsAllPages(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-sandbox-service/api/v1/sandboxes/1/vms", request.URL.Path)
+		assert.Equal(t, "100", request.URL.Query().Get("page_size"))
+
+		page := request.URL.Query().Get("page")
+		_, _ = fmt.Fprintf(writer, `{"page": %s, "page_size": 100, "page_count": 2, "count": 1, "total_count": 2,
+			"results": [{"name": "server-%s"}]}`, page, page)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.GetSandboxVMs(context.Background(), "1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []kypo.VirtualMachine{{Name: "server-1"}, {Name: "server-2"}}, actual)
+}
+
+func assertPerformVMAction(t *testing.T, request *http.Request, action string) {
+	assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
+	assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+	assert.Equal(t, "/kypo-sandbox-service/api/v1/sandboxes/1/vms/server", request.URL.Path)
+	assert.Equal(t, http.MethodPatch, request.Method)
+
+	body, err := io.ReadAll(request.Body)
+	assert.NoError(t, err)
+	assert.JSONEq(t, fmt.Sprintf(`{"action": "%s"}`, action), string(body))
+}
+
+func TestPerformVMActionSuccessful(t *testing.T) {
+	for _, action := range []kypo.VMAction{kypo.VMActionResume, kypo.VMActionReboot, kypo.VMActionSuspend} {
+		ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			assertPerformVMAction(t, request, string(action))
+		}))
+
+		c := minimalClient(ts)
+
+		err := c.PerformVMAction(context.Background(), "1", "server", action)
+
+		assert.NoError(t, err)
+		ts.Close()
+	}
+}
+
+func TestPerformVMActionNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertPerformVMAction(t, request, "reboot")
+
+		writer.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.Error{
+		ResourceName: "sandbox virtual machine",
+		Identifier:   "server in sandbox 1",
+		Err:          kypo.ErrNotFound,
+	}
+
+	err := c.PerformVMAction(context.Background(), "1", "server", kypo.VMActionReboot)
+
+	assert.Equal(t, expected, err)
+}
+
+func TestPerformVMActionUnknownAction(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		t.Error("no request should be sent")
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	err := c.PerformVMAction(context.Background(), "1", "server", "shutdown")
+
+	assert.EqualError(t, err, `unknown virtual machine action "shutdown"`)
+}
+
+func TestGetVMConsoleSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+		assert.Equal(t, "/kypo-sandbox-service/api/v1/sandboxes/1/vms/server/console", request.URL.Path)
+		assert.Equal(t, http.MethodGet, request.Method)
+
+		_, _ = fmt.Fprint(writer, `{"url": "https://console.ex/vnc_auto.html?token=token"}`)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.GetVMConsole(context.Background(), "1", "server")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://console.ex/vnc_auto.html?token=token", actual)
+}
@@ -0,0 +1,281 @@
+package kypo
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SSHAccess is the content of an SSH access archive provided by KYPO.
+type SSHAccess struct {
+	// SSHConfig is the content of the first ssh config file from the archive.
+	SSHConfig string
+
+	// PrivateKey is the content of the first private key from the archive.
+	PrivateKey string
+
+	// PublicKey is the content of the first public key from the archive.
+	PublicKey string
+
+	// JumpHost is the address of the host which is used to access the sandbox hosts, as specified in SSHConfig.
+	JumpHost string
+
+	// Directories contains the ssh configs and keys of each directory of the archive in the order of their paths.
+	// A management access archive of a sandbox pool contains a directory for each sandbox.
+	Directories []SSHAccessDirectory
+
+	// Files contains all files from the archive by their paths in the archive.
+	Files map[string][]byte
+}
+
+// SSHAccessDirectory contains the ssh configs and keys of a single directory of an SSH access archive.
+type SSHAccessDirectory struct {
+	// Path of the directory in the archive, "." for the top level.
+	Path string
+
+	// SSHConfigs are the contents of the ssh config files by their paths in the archive.
+	SSHConfigs map[string]string
+
+	// PrivateKeys are the contents of the private keys by their paths in the archive.
+	PrivateKeys map[string]string
+
+	// PublicKeys are the contents of the public keys by their paths in the archive.
+	PublicKeys map[string]string
+}
+
+// GetSandboxUserSSHAccess downloads the zip archive with SSH configuration and keys for user access to the given sandbox.
+// Use ParseSSHAccess to read the content of the archive.
+func (c *Client) GetSandboxUserSSHAccess(ctx context.Context, sandboxId string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/kypo-sandbox-service/api/v1/sandboxes/%s/user-ssh-access", c.Endpoint, sandboxId), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("accept", "application/zip")
+
+	body, _, err := c.doRequestWithRetry(req, http.StatusOK, "sandbox user ssh access", sandboxId)
+	if err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// GetSandboxPoolManagementSSHAccess downloads the zip archive with SSH configuration and keys for management access
+// to all sandboxes of the given sandbox pool. Use ParseSSHAccess to read the content of the archive.
+func (c *Client) GetSandboxPoolManagementSSHAccess(ctx context.Context, poolId int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/kypo-sandbox-service/api/v1/pools/%d/management-ssh-access", c.Endpoint, poolId), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("accept", "application/zip")
+
+	body, _, err := c.doRequestWithRetry(req, http.StatusOK, "sandbox pool management ssh access", poolId)
+	if err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// ParseSSHAccess reads an SSH access archive downloaded by GetSandboxUserSSHAccess or GetSandboxPoolManagementSSHAccess.
+// Files named `config` or ending with `-config` are ssh configs, files ending with `-key` are private keys
+// and files ending with `-key.pub` are public keys.
+func ParseSSHAccess(archive []byte) (*SSHAccess, error) {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, err
+	}
+
+	access := SSHAccess{Files: map[string][]byte{}}
+	directories := map[string]*SSHAccessDirectory{}
+	var paths []string
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		name := path.Clean(file.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("ssh access archive contains invalid path %q", file.Name)
+		}
+		content, err := readZipFile(file)
+		if err != nil {
+			return nil, err
+		}
+		access.Files[name] = content
+		paths = append(paths, name)
+	}
+	sort.Strings(paths)
+
+	for _, name := range paths {
+		content := string(access.Files[name])
+		dir := path.Dir(name)
+		directory, ok := directories[dir]
+		if !ok {
+			directory = &SSHAccessDirectory{Path: dir, SSHConfigs: map[string]string{}, PrivateKeys: map[string]string{}, PublicKeys: map[string]string{}}
+			directories[dir] = directory
+		}
+
+		base := path.Base(name)
+		switch {
+		case strings.HasSuffix(base, "-key.pub"):
+			directory.PublicKeys[name] = content
+			if access.PublicKey == "" {
+				access.PublicKey = content
+			}
+		case strings.HasSuffix(base, "-key"):
+			directory.PrivateKeys[name] = content
+			if access.PrivateKey == "" {
+				access.PrivateKey = content
+			}
+		case base == "config" || strings.HasSuffix(base, "-config"):
+			directory.SSHConfigs[name] = content
+			if access.SSHConfig == "" {
+				access.SSHConfig = content
+			}
+		}
+	}
+
+	if access.SSHConfig == "" {
+		return nil, fmt.Errorf("ssh access archive does not contain ssh config")
+	}
+	access.JumpHost = findJumpHost(access.SSHConfig)
+
+	dirs := make([]string, 0, len(directories))
+	for dir := range directories {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		access.Directories = append(access.Directories, *directories[dir])
+	}
+
+	return &access, nil
+}
+
+// WriteToDirectory writes all files of the SSH access archive to the directory `dir` with their paths in the archive.
+// The directories are created if needed. Private keys are written with permissions which are accepted by OpenSSH.
+// The `IdentityFile` options of the ssh configs are rewritten to point to the keys in `dir`.
+func (a *SSHAccess) WriteToDirectory(dir string) error {
+	privateKeys := map[string]bool{}
+	configs := map[string]bool{}
+	for _, directory := range a.Directories {
+		for name := range directory.PrivateKeys {
+			privateKeys[name] = true
+		}
+		for name := range directory.SSHConfigs {
+			configs[name] = true
+		}
+	}
+
+	for name, content := range a.Files {
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		err := os.MkdirAll(filepath.Dir(target), 0o700)
+		if err != nil {
+			return err
+		}
+
+		mode := os.FileMode(0o644)
+		if privateKeys[name] {
+			mode = 0o600
+		}
+		if configs[name] {
+			content = []byte(rewriteIdentityFiles(string(content), dir, path.Dir(name), a.Files))
+		}
+		err = os.WriteFile(target, content, mode)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readZipFile(file *zip.File) (content []byte, err error) {
+	reader, err := file.Open()
+	if err != nil {
+		return
+	}
+	defer func() {
+		err2 := reader.Close()
+		if err == nil {
+			err = err2
+		}
+	}()
+
+	return io.ReadAll(reader)
+}
+
+// sshConfigOptions returns the options of each Host block of the ssh config.
+func sshConfigOptions(config string) map[string]map[string]string {
+	hosts := map[string]map[string]string{}
+	var current map[string]string
+
+	scanner := bufio.NewScanner(strings.NewReader(config))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		key := strings.ToLower(fields[0])
+		if key == "host" {
+			current = map[string]string{}
+			for _, host := range fields[1:] {
+				hosts[host] = current
+			}
+			continue
+		}
+		if current != nil {
+			current[key] = fields[1]
+		}
+	}
+
+	return hosts
+}
+
+func findJumpHost(config string) string {
+	scanner := bufio.NewScanner(strings.NewReader(config))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.ToLower(fields[0]) != "proxyjump" {
+			continue
+		}
+		jump := fields[1]
+		if options, ok := sshConfigOptions(config)[jump]; ok && options["hostname"] != "" {
+			return options["hostname"]
+		}
+		return jump
+	}
+	return ""
+}
+
+// rewriteIdentityFiles points the `IdentityFile` options of the config in the archive directory `configDir`
+// to the keys written to `dir`. A key is looked up in `configDir` first and then at the top level of the archive.
+func rewriteIdentityFiles(config, dir, configDir string, files map[string][]byte) string {
+	lines := strings.Split(config, "\n")
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || strings.ToLower(fields[0]) != "identityfile" {
+			continue
+		}
+		base := path.Base(fields[1])
+		name := path.Join(configDir, base)
+		if _, ok := files[name]; !ok {
+			name = base
+			if _, ok := files[name]; !ok {
+				continue
+			}
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		lines[i] = fmt.Sprintf("%s%s %s", indent, fields[0], filepath.Join(dir, filepath.FromSlash(name)))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -5,9 +5,62 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// TrainingDefinitionState is the state of a linear or adaptive training definition.
+// Only released training definitions can be used by training instances.
+type TrainingDefinitionState string
+
+const (
+	TrainingDefinitionStateUnreleased TrainingDefinitionState = "UNRELEASED"
+	TrainingDefinitionStateReleased   TrainingDefinitionState = "RELEASED"
+	TrainingDefinitionStateArchived   TrainingDefinitionState = "ARCHIVED"
+)
+
+// TrainingDefinitionSummary is a training definition as listed by ListTrainingDefinitions, without its levels or phases.
+type TrainingDefinitionSummary struct {
+	Id         int64                   `json:"id" tfsdk:"id"`
+	Title      string                  `json:"title" tfsdk:"title"`
+	State      TrainingDefinitionState `json:"state" tfsdk:"state"`
+	LastEdited time.Time               `json:"last_edited" tfsdk:"last_edited"`
+	Authors    []User                  `json:"authors" tfsdk:"authors"`
+}
+
+// ListTrainingDefinitionsOptions filter and paginate the training definitions listed by ListTrainingDefinitions.
+// Zero values are not used as filters.
+type ListTrainingDefinitionsOptions struct {
+	// Page to return, the first page is 1.
+	Page int64
+	// PageSize is the number of definitions per page. KYPO uses its default page size when it is 0.
+	PageSize int64
+	// Title of the definitions to return.
+	Title string
+	// State of the definitions to return.
+	State TrainingDefinitionState
+	// Author is the sub, mail or full name of an author of the definitions to return. KYPO cannot filter
+	// by the author, so all pages matching the other filters are read and the filtered definitions are paginated
+	// by the client. All of them are returned on a single page when PageSize is 0.
+	Author string
+}
+
+type trainingDefinitionSummaryResponse struct {
+	TrainingDefinitionSummary
+	Authors []userRef `json:"authors"`
+}
+
+func (r *trainingDefinitionSummaryResponse) toSummary() TrainingDefinitionSummary {
+	summary := r.TrainingDefinitionSummary
+	summary.Authors = make([]User, 0, len(r.Authors))
+	for _, author := range r.Authors {
+		summary.Authors = append(summary.Authors, author.toUser())
+	}
+	return summary
+}
+
 type TrainingDefinition struct {
 	Id      int64  `json:"id" tfsdk:"id"`
 	Content string `json:"content" tfsdk:"content"`
@@ -67,16 +120,224 @@ func (c *Client) CreateTrainingDefinition(ctx context.Context, content string) (
 }
 
 // DeleteTrainingDefinition deletes the definition given by definitionID.
+// If KYPO refuses to delete the definition because it is released, the training instances are checked.
+// When no training instance uses the definition, it is unreleased and deleted. Otherwise, an Error wrapping
+// ErrTrainingDefinitionReleased is returned without retrying the request. The error lists the training instances which use the definition.
 func (c *Client) DeleteTrainingDefinition(ctx context.Context, definitionID int64) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/kypo-rest-training/api/v1/training-definitions/%d", c.Endpoint, definitionID), nil)
+	return c.deleteTrainingDefinition(ctx, "kypo-rest-training", "training definition", "training instances", definitionID)
+}
+
+// SetTrainingDefinitionState changes the state of the definition given by definitionID.
+func (c *Client) SetTrainingDefinitionState(ctx context.Context, definitionID int64, state TrainingDefinitionState) error {
+	return c.setTrainingDefinitionState(ctx, "kypo-rest-training", "training definition", definitionID, state)
+}
+
+// GetTrainingDefinitionState reads the current state of the definition given by definitionID.
+func (c *Client) GetTrainingDefinitionState(ctx context.Context, definitionID int64) (TrainingDefinitionState, error) {
+	return c.getTrainingDefinitionState(ctx, "kypo-rest-training", "training definition", definitionID)
+}
+
+// ListTrainingDefinitions lists the training definitions matching the given `options`.
+func (c *Client) ListTrainingDefinitions(ctx context.Context, options ListTrainingDefinitionsOptions) (*Pagination[[]TrainingDefinitionSummary], error) {
+	return c.listTrainingDefinitions(ctx, "kypo-rest-training", "training definitions", options)
+}
+
+func (c *Client) listTrainingDefinitions(ctx context.Context, service, resourceName string, options ListTrainingDefinitionsOptions) (*Pagination[[]TrainingDefinitionSummary], error) {
+	if options.Author != "" {
+		return c.listTrainingDefinitionsByAuthor(ctx, service, resourceName, options)
+	}
+
+	query := url.Values{}
+	if options.Page > 0 {
+		query.Set("page", strconv.FormatInt(options.Page-1, 10))
+	}
+	if options.PageSize > 0 {
+		query.Set("size", strconv.FormatInt(options.PageSize, 10))
+	}
+	if options.Title != "" {
+		query.Set("title", options.Title)
+	}
+	if options.State != "" {
+		query.Set("state", string(options.State))
+	}
+
+	listURL := fmt.Sprintf("%s/%s/api/v1/training-definitions", c.Endpoint, service)
+	if len(query) > 0 {
+		listURL += "?" + query.Encode()
+	}
+
+	var page javaPage[[]trainingDefinitionSummaryResponse]
+	err := c.doJSONRequest(ctx, http.MethodGet, listURL, nil, &page, http.StatusOK, resourceName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	pagination := page.toPagination()
+	definitions := Pagination[[]TrainingDefinitionSummary]{
+		Page:       pagination.Page,
+		PageSize:   pagination.PageSize,
+		PageCount:  pagination.PageCount,
+		Count:      pagination.Count,
+		TotalCount: pagination.TotalCount,
+		Results:    make([]TrainingDefinitionSummary, 0, len(page.Content)),
+	}
+	for _, definition := range page.Content {
+		definitions.Results = append(definitions.Results, definition.toSummary())
+	}
+
+	return &definitions, nil
+}
+
+// listTrainingDefinitionsByAuthor reads all pages matching the title and state of the `options`, keeps the definitions
+// of the author and returns the requested page of them.
+func (c *Client) listTrainingDefinitionsByAuthor(ctx context.Context, service, resourceName string, options ListTrainingDefinitionsOptions) (*Pagination[[]TrainingDefinitionSummary], error) {
+	query := url.Values{}
+	if options.Title != "" {
+		query.Set("title", options.Title)
+	}
+	if options.State != "" {
+		query.Set("state", string(options.State))
+	}
+
+	listURL := fmt.Sprintf("%s/%s/api/v1/training-definitions", c.Endpoint, service)
+	if len(query) > 0 {
+		listURL += "?" + query.Encode()
+	}
+
+	responses, err := getAllJavaPages[trainingDefinitionSummaryResponse](ctx, c, listURL, resourceName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []TrainingDefinitionSummary
+	for _, response := range responses {
+		summary := response.toSummary()
+		if hasAuthor(summary.Authors, options.Author) {
+			matching = append(matching, summary)
+		}
+	}
+
+	page := options.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := options.PageSize
+	if pageSize <= 0 {
+		pageSize = int64(len(matching))
+	}
+	total := int64(len(matching))
+	var pageCount int64
+	if pageSize > 0 {
+		pageCount = (total + pageSize - 1) / pageSize
+	}
+
+	start := (page - 1) * pageSize
+	if start > total {
+		start = total
+	}
+	end := start + pageSize
+	if end > total {
+		end = total
+	}
+	results := make([]TrainingDefinitionSummary, 0, end-start)
+	results = append(results, matching[start:end]...)
+
+	return &Pagination[[]TrainingDefinitionSummary]{
+		Page:       page,
+		PageSize:   pageSize,
+		PageCount:  pageCount,
+		Count:      int64(len(results)),
+		TotalCount: total,
+		Results:    results,
+	}, nil
+}
+
+func hasAuthor(authors []User, author string) bool {
+	for _, user := range authors {
+		if user.Sub == author || user.Mail == author || user.FullName == author {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) deleteTrainingDefinition(ctx context.Context, service, resourceName, instancesResourceName string, definitionID int64) error {
+	statusCode, err := c.deleteTrainingDefinitionRequest(ctx, service, resourceName, definitionID)
+	if err == nil || (statusCode != http.StatusConflict && statusCode != http.StatusBadRequest) {
+		return err
+	}
+
+	state, stateErr := c.getTrainingDefinitionState(ctx, service, resourceName, definitionID)
+	if stateErr != nil || state != TrainingDefinitionStateReleased {
+		return err
+	}
+
+	instances, instancesErr := c.trainingDefinitionInstances(ctx, service, instancesResourceName, definitionID)
+	if instancesErr != nil {
+		return &Error{ResourceName: resourceName, Identifier: definitionID, Err: ErrTrainingDefinitionReleased}
+	}
+	if len(instances) > 0 {
+		return &Error{ResourceName: resourceName, Identifier: definitionID,
+			Err: fmt.Errorf("%w and used by training instances %s", ErrTrainingDefinitionReleased, strings.Join(instances, ", "))}
+	}
+
+	// No training instance uses the definition, so it can be unreleased and deleted
+	err = c.setTrainingDefinitionState(ctx, service, resourceName, definitionID, TrainingDefinitionStateUnreleased)
 	if err != nil {
 		return err
 	}
+	_, err = c.deleteTrainingDefinitionRequest(ctx, service, resourceName, definitionID)
+	return err
+}
+
+func (c *Client) deleteTrainingDefinitionRequest(ctx context.Context, service, resourceName string, definitionID int64) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/%s/api/v1/training-definitions/%d", c.Endpoint, service, definitionID), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	// KYPO refuses to delete released definitions, repeating the request would not help
+	_, statusCode, err := c.doRequestWithRetry(req, http.StatusOK, resourceName, definitionID, http.StatusConflict, http.StatusBadRequest)
+	return statusCode, err
+}
 
-	_, _, err = c.doRequestWithRetry(req, http.StatusOK, "training definition", definitionID)
+// trainingDefinitionInstances returns the ids of the training instances which use the definition.
+func (c *Client) trainingDefinitionInstances(ctx context.Context, service, instancesResourceName string, definitionID int64) ([]string, error) {
+	instances, err := c.listTrainingInstances(ctx, service, instancesResourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, instance := range instances {
+		if instance.TrainingDefinitionId == definitionID {
+			ids = append(ids, strconv.FormatInt(instance.Id, 10))
+		}
+	}
+	return ids, nil
+}
+
+func (c *Client) setTrainingDefinitionState(ctx context.Context, service, resourceName string, definitionID int64, state TrainingDefinitionState) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/%s/api/v1/training-definitions/%d/states/%s", c.Endpoint, service, definitionID, state), nil)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = c.doRequestWithRetry(req, http.StatusNoContent, resourceName, definitionID)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func (c *Client) getTrainingDefinitionState(ctx context.Context, service, resourceName string, definitionID int64) (TrainingDefinitionState, error) {
+	definition := struct {
+		State TrainingDefinitionState `json:"state"`
+	}{}
+	err := c.doJSONRequest(ctx, http.MethodGet, fmt.Sprintf("%s/%s/api/v1/training-definitions/%d", c.Endpoint, service, definitionID), nil, &definition, http.StatusOK, resourceName, definitionID)
+	if err != nil {
+		return "", err
+	}
+	return definition.State, nil
+}
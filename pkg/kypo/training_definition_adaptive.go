@@ -67,16 +67,24 @@ func (c *Client) CreateTrainingDefinitionAdaptive(ctx context.Context, content s
 }
 
 // DeleteTrainingDefinitionAdaptive deletes the adaptive definition given by definitionID.
+// If KYPO refuses to delete the definition because it is released, the training instances are checked.
+// When no training instance uses the definition, it is unreleased and deleted. Otherwise, an Error wrapping
+// ErrTrainingDefinitionReleased is returned without retrying the request. The error lists the training instances which use the definition.
 func (c *Client) DeleteTrainingDefinitionAdaptive(ctx context.Context, definitionID int64) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/kypo-adaptive-training/api/v1/training-definitions/%d", c.Endpoint, definitionID), nil)
-	if err != nil {
-		return err
-	}
+	return c.deleteTrainingDefinition(ctx, "kypo-adaptive-training", "training definition adaptive", "training instances adaptive", definitionID)
+}
 
-	_, _, err = c.doRequestWithRetry(req, http.StatusOK, "training definition adaptive", definitionID)
-	if err != nil {
-		return err
-	}
+// ListTrainingDefinitionsAdaptive lists the adaptive training definitions matching the given `options`.
+func (c *Client) ListTrainingDefinitionsAdaptive(ctx context.Context, options ListTrainingDefinitionsOptions) (*Pagination[[]TrainingDefinitionSummary], error) {
+	return c.listTrainingDefinitions(ctx, "kypo-adaptive-training", "training definitions adaptive", options)
+}
+
+// SetTrainingDefinitionAdaptiveState changes the state of the adaptive definition given by definitionID.
+func (c *Client) SetTrainingDefinitionAdaptiveState(ctx context.Context, definitionID int64, state TrainingDefinitionState) error {
+	return c.setTrainingDefinitionState(ctx, "kypo-adaptive-training", "training definition adaptive", definitionID, state)
+}
 
-	return nil
+// GetTrainingDefinitionAdaptiveState reads the current state of the adaptive definition given by definitionID.
+func (c *Client) GetTrainingDefinitionAdaptiveState(ctx context.Context, definitionID int64) (TrainingDefinitionState, error) {
+	return c.getTrainingDefinitionState(ctx, "kypo-adaptive-training", "training definition adaptive", definitionID)
 }
@@ -0,0 +1,348 @@
+package kypo
+
+import (
+	"encoding/json"
+)
+
+// AdaptiveTrainingDefinitionSpec is the content of an exported adaptive training definition,
+// see TrainingDefinitionAdaptive.Content. Fields which are not modelled are preserved,
+// so parsing and marshalling the content again is lossless.
+type AdaptiveTrainingDefinitionSpec struct {
+	Title             string                  `json:"title"`
+	Description       string                  `json:"description"`
+	Prerequisites     []string                `json:"prerequisites"`
+	Outcomes          []string                `json:"outcomes"`
+	State             TrainingDefinitionState `json:"state"`
+	ShowStepperBar    bool                    `json:"show_stepper_bar"`
+	EstimatedDuration int64                   `json:"estimated_duration"`
+	Phases            Phases                  `json:"phases"`
+
+	fields map[string]json.RawMessage
+}
+
+// PhaseType is the type of phase of an adaptive training definition.
+type PhaseType string
+
+const (
+	PhaseTypeInfo          PhaseType = "INFO"
+	PhaseTypeQuestionnaire PhaseType = "QUESTIONNAIRE"
+	PhaseTypeTraining      PhaseType = "TRAINING"
+	PhaseTypeAccess        PhaseType = "ACCESS"
+)
+
+// Phase is a phase of an adaptive training definition. It is one of *InfoPhase, *QuestionnairePhase,
+// *TrainingPhase, *AccessPhase or *UnknownPhase for phase types not known to this library.
+type Phase interface {
+	// Base returns the fields common to all phases.
+	Base() *PhaseBase
+}
+
+// Phases is a list of phases which is unmarshalled to the concrete phase types based on their `phase_type`.
+type Phases []Phase
+
+// PhaseBase contains the fields common to all phases.
+type PhaseBase struct {
+	Title     string    `json:"title"`
+	PhaseType PhaseType `json:"phase_type"`
+	Order     int64     `json:"order"`
+}
+
+type InfoPhase struct {
+	PhaseBase
+	Content string `json:"content"`
+
+	fields map[string]json.RawMessage
+}
+
+type AccessPhase struct {
+	PhaseBase
+	Passkey      string `json:"passkey"`
+	CloudContent string `json:"cloud_content"`
+	LocalContent string `json:"local_content"`
+
+	fields map[string]json.RawMessage
+}
+
+type QuestionnairePhase struct {
+	PhaseBase
+	QuestionnaireType string                  `json:"questionnaire_type"`
+	Questions         []QuestionnaireQuestion `json:"questions"`
+	PhaseRelations    []QuestionPhaseRelation `json:"phase_relations"`
+
+	fields map[string]json.RawMessage
+}
+
+type QuestionnaireQuestion struct {
+	QuestionType string                `json:"question_type"`
+	Text         string                `json:"text"`
+	Order        int64                 `json:"order"`
+	Choices      []QuestionnaireChoice `json:"choices"`
+
+	fields map[string]json.RawMessage
+}
+
+type QuestionnaireChoice struct {
+	Text    string `json:"text"`
+	Correct bool   `json:"correct"`
+	Order   int64  `json:"order"`
+
+	fields map[string]json.RawMessage
+}
+
+// QuestionPhaseRelation relates the questions of an adaptive questionnaire to the training phase,
+// whose task is selected based on the success rate of the answers to the questions.
+type QuestionPhaseRelation struct {
+	Order          int64   `json:"order"`
+	SuccessRate    int64   `json:"success_rate"`
+	PhaseOrder     int64   `json:"phase_order"`
+	QuestionOrders []int64 `json:"question_orders"`
+
+	fields map[string]json.RawMessage
+}
+
+type TrainingPhase struct {
+	PhaseBase
+	EstimatedDuration   int64               `json:"estimated_duration"`
+	AllowedCommands     int64               `json:"allowed_commands"`
+	AllowedWrongAnswers int64               `json:"allowed_wrong_answers"`
+	Tasks               []Task              `json:"tasks"`
+	DecisionMatrix      []DecisionMatrixRow `json:"decision_matrix"`
+	MitreTechniques     []MitreTechnique    `json:"mitre_techniques"`
+	ExpectedCommands    []string            `json:"expected_commands"`
+
+	fields map[string]json.RawMessage
+}
+
+type Task struct {
+	Title                         string `json:"title"`
+	Content                       string `json:"content"`
+	Answer                        string `json:"answer"`
+	Solution                      string `json:"solution"`
+	IncorrectAnswerLimit          int64  `json:"incorrect_answer_limit"`
+	ModifySandbox                 bool   `json:"modify_sandbox"`
+	SandboxChangeExpectedDuration int64  `json:"sandbox_change_expected_duration"`
+	Order                         int64  `json:"order"`
+
+	fields map[string]json.RawMessage
+}
+
+// DecisionMatrixRow contains the weights of the participant's performance in the training phase
+// with the same order as the row. The decision matrix of a training phase has one row
+// for each preceding training phase and one for the phase itself.
+type DecisionMatrixRow struct {
+	Order              int64   `json:"order"`
+	AssessmentAnswered float64 `json:"assessment_answered"`
+	KeywordUsed        float64 `json:"keyword_used"`
+	CompletedInTime    float64 `json:"completed_in_time"`
+	SolutionDisplayed  float64 `json:"solution_displayed"`
+	WrongAnswers       float64 `json:"wrong_answers"`
+
+	fields map[string]json.RawMessage
+}
+
+// UnknownPhase is a phase of a type which is not known to this library. All its fields are preserved.
+type UnknownPhase struct {
+	PhaseBase
+
+	fields map[string]json.RawMessage
+}
+
+// ParseAdaptiveTrainingDefinition parses the content of an exported adaptive training definition.
+func ParseAdaptiveTrainingDefinition(content string) (*AdaptiveTrainingDefinitionSpec, error) {
+	spec := AdaptiveTrainingDefinitionSpec{}
+	err := json.Unmarshal([]byte(content), &spec)
+	if err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// Spec parses the Content of the adaptive training definition.
+func (d *TrainingDefinitionAdaptive) Spec() (*AdaptiveTrainingDefinitionSpec, error) {
+	return ParseAdaptiveTrainingDefinition(d.Content)
+}
+
+// Content marshals the spec to a JSON string, which can be imported by CreateTrainingDefinitionAdaptive.
+func (s *AdaptiveTrainingDefinitionSpec) Content() (string, error) {
+	content, err := marshalJSON(s)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+func (p *Phases) UnmarshalJSON(data []byte) error {
+	var rawPhases []json.RawMessage
+	err := json.Unmarshal(data, &rawPhases)
+	if err != nil {
+		return err
+	}
+	if rawPhases == nil {
+		*p = nil
+		return nil
+	}
+
+	phases := make(Phases, 0, len(rawPhases))
+	for _, rawPhase := range rawPhases {
+		phaseType := struct {
+			PhaseType PhaseType `json:"phase_type"`
+		}{}
+		err = json.Unmarshal(rawPhase, &phaseType)
+		if err != nil {
+			return err
+		}
+
+		var phase Phase
+		switch phaseType.PhaseType {
+		case PhaseTypeInfo:
+			phase = &InfoPhase{}
+		case PhaseTypeQuestionnaire:
+			phase = &QuestionnairePhase{}
+		case PhaseTypeTraining:
+			phase = &TrainingPhase{}
+		case PhaseTypeAccess:
+			phase = &AccessPhase{}
+		default:
+			phase = &UnknownPhase{}
+		}
+		err = json.Unmarshal(rawPhase, phase)
+		if err != nil {
+			return err
+		}
+		phases = append(phases, phase)
+	}
+
+	*p = phases
+	return nil
+}
+
+func (p *InfoPhase) Base() *PhaseBase          { return &p.PhaseBase }
+func (p *AccessPhase) Base() *PhaseBase        { return &p.PhaseBase }
+func (p *QuestionnairePhase) Base() *PhaseBase { return &p.PhaseBase }
+func (p *TrainingPhase) Base() *PhaseBase      { return &p.PhaseBase }
+func (p *UnknownPhase) Base() *PhaseBase       { return &p.PhaseBase }
+
+func (s *AdaptiveTrainingDefinitionSpec) UnmarshalJSON(data []byte) (err error) {
+	type plain AdaptiveTrainingDefinitionSpec
+	s.fields, err = unmarshalObject(data, (*plain)(s))
+	return
+}
+
+func (s AdaptiveTrainingDefinitionSpec) MarshalJSON() ([]byte, error) {
+	type plain AdaptiveTrainingDefinitionSpec
+	return marshalObject(plain(s), s.fields)
+}
+
+func (p *InfoPhase) UnmarshalJSON(data []byte) (err error) {
+	type plain InfoPhase
+	p.fields, err = unmarshalObject(data, (*plain)(p))
+	return
+}
+
+func (p InfoPhase) MarshalJSON() ([]byte, error) {
+	type plain InfoPhase
+	p.PhaseType = PhaseTypeInfo
+	return marshalObject(plain(p), p.fields)
+}
+
+func (p *AccessPhase) UnmarshalJSON(data []byte) (err error) {
+	type plain AccessPhase
+	p.fields, err = unmarshalObject(data, (*plain)(p))
+	return
+}
+
+func (p AccessPhase) MarshalJSON() ([]byte, error) {
+	type plain AccessPhase
+	p.PhaseType = PhaseTypeAccess
+	return marshalObject(plain(p), p.fields)
+}
+
+func (p *QuestionnairePhase) UnmarshalJSON(data []byte) (err error) {
+	type plain QuestionnairePhase
+	p.fields, err = unmarshalObject(data, (*plain)(p))
+	return
+}
+
+func (p QuestionnairePhase) MarshalJSON() ([]byte, error) {
+	type plain QuestionnairePhase
+	p.PhaseType = PhaseTypeQuestionnaire
+	return marshalObject(plain(p), p.fields)
+}
+
+func (p *TrainingPhase) UnmarshalJSON(data []byte) (err error) {
+	type plain TrainingPhase
+	p.fields, err = unmarshalObject(data, (*plain)(p))
+	return
+}
+
+func (p TrainingPhase) MarshalJSON() ([]byte, error) {
+	type plain TrainingPhase
+	p.PhaseType = PhaseTypeTraining
+	return marshalObject(plain(p), p.fields)
+}
+
+func (p *UnknownPhase) UnmarshalJSON(data []byte) (err error) {
+	type plain UnknownPhase
+	p.fields, err = unmarshalObject(data, (*plain)(p))
+	return
+}
+
+func (p UnknownPhase) MarshalJSON() ([]byte, error) {
+	type plain UnknownPhase
+	return marshalObject(plain(p), p.fields)
+}
+
+func (q *QuestionnaireQuestion) UnmarshalJSON(data []byte) (err error) {
+	type plain QuestionnaireQuestion
+	q.fields, err = unmarshalObject(data, (*plain)(q))
+	return
+}
+
+func (q QuestionnaireQuestion) MarshalJSON() ([]byte, error) {
+	type plain QuestionnaireQuestion
+	return marshalObject(plain(q), q.fields)
+}
+
+func (c *QuestionnaireChoice) UnmarshalJSON(data []byte) (err error) {
+	type plain QuestionnaireChoice
+	c.fields, err = unmarshalObject(data, (*plain)(c))
+	return
+}
+
+func (c QuestionnaireChoice) MarshalJSON() ([]byte, error) {
+	type plain QuestionnaireChoice
+	return marshalObject(plain(c), c.fields)
+}
+
+func (r *QuestionPhaseRelation) UnmarshalJSON(data []byte) (err error) {
+	type plain QuestionPhaseRelation
+	r.fields, err = unmarshalObject(data, (*plain)(r))
+	return
+}
+
+func (r QuestionPhaseRelation) MarshalJSON() ([]byte, error) {
+	type plain QuestionPhaseRelation
+	return marshalObject(plain(r), r.fields)
+}
+
+func (t *Task) UnmarshalJSON(data []byte) (err error) {
+	type plain Task
+	t.fields, err = unmarshalObject(data, (*plain)(t))
+	return
+}
+
+func (t Task) MarshalJSON() ([]byte, error) {
+	type plain Task
+	return marshalObject(plain(t), t.fields)
+}
+
+func (r *DecisionMatrixRow) UnmarshalJSON(data []byte) (err error) {
+	type plain DecisionMatrixRow
+	r.fields, err = unmarshalObject(data, (*plain)(r))
+	return
+}
+
+func (r DecisionMatrixRow) MarshalJSON() ([]byte, error) {
+	type plain DecisionMatrixRow
+	return marshalObject(plain(r), r.fields)
+}
@@ -0,0 +1,103 @@
+package kypo_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+	"testing"
+)
+
+func TestParseAdaptiveTrainingDefinition(t *testing.T) {
+	spec, err := kypo.ParseAdaptiveTrainingDefinition(readTestdata(t, "training_definition_adaptive.json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Adaptive junior hacker", spec.Title)
+	assert.Equal(t, int64(60), spec.EstimatedDuration)
+	require.Len(t, spec.Phases, 5)
+
+	info, ok := spec.Phases[0].(*kypo.InfoPhase)
+	require.True(t, ok)
+	assert.Equal(t, "Welcome to the <i>adaptive</i> training.", info.Content)
+
+	access, ok := spec.Phases[1].(*kypo.AccessPhase)
+	require.True(t, ok)
+	assert.Equal(t, "start-training", access.Passkey)
+
+	questionnaire, ok := spec.Phases[2].(*kypo.QuestionnairePhase)
+	require.True(t, ok)
+	assert.Equal(t, "ADAPTIVE", questionnaire.QuestionnaireType)
+	require.Len(t, questionnaire.Questions, 2)
+	assert.True(t, questionnaire.Questions[0].Choices[0].Correct)
+	require.Len(t, questionnaire.PhaseRelations, 2)
+	assert.Equal(t, int64(3), questionnaire.PhaseRelations[0].PhaseOrder)
+	assert.Equal(t, []int64{0, 1}, questionnaire.PhaseRelations[0].QuestionOrders)
+	assert.Equal(t, int64(50), questionnaire.PhaseRelations[0].SuccessRate)
+
+	training, ok := spec.Phases[4].(*kypo.TrainingPhase)
+	require.True(t, ok)
+	assert.Equal(t, kypo.PhaseTypeTraining, training.PhaseType)
+	assert.Equal(t, int64(5), training.AllowedWrongAnswers)
+	require.Len(t, training.Tasks, 1)
+	assert.True(t, training.Tasks[0].ModifySandbox)
+	require.Len(t, training.DecisionMatrix, 2)
+	assert.Equal(t, 0.25, training.DecisionMatrix[1].WrongAnswers)
+
+	for i, phase := range spec.Phases {
+		assert.Equal(t, int64(i), phase.Base().Order)
+	}
+}
+
+func TestAdaptiveTrainingDefinitionRoundTrip(t *testing.T) {
+	content := readTestdata(t, "training_definition_adaptive.json")
+
+	spec, err := kypo.ParseAdaptiveTrainingDefinition(content)
+	require.NoError(t, err)
+
+	actual, err := spec.Content()
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, content, actual)
+}
+
+func TestAdaptiveTrainingDefinitionRoundTripMinimal(t *testing.T) {
+	definition := kypo.TrainingDefinitionAdaptive{Id: 1, Content: trainingDefinitionAdaptiveJsonString}
+
+	spec, err := definition.Spec()
+	require.NoError(t, err)
+
+	actual, err := spec.Content()
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, trainingDefinitionAdaptiveJsonString, actual)
+}
+
+func TestAdaptiveTrainingDefinitionNew(t *testing.T) {
+	spec := kypo.AdaptiveTrainingDefinitionSpec{
+		Title: "title",
+		State: kypo.TrainingDefinitionStateUnreleased,
+		Phases: kypo.Phases{
+			&kypo.TrainingPhase{
+				PhaseBase: kypo.PhaseBase{Title: "training"},
+				Tasks: []kypo.Task{
+					{Title: "task", Answer: "answer"},
+				},
+				DecisionMatrix: []kypo.DecisionMatrixRow{
+					{AssessmentAnswered: 1},
+				},
+			},
+		},
+	}
+
+	content, err := spec.Content()
+	require.NoError(t, err)
+
+	parsed, err := kypo.ParseAdaptiveTrainingDefinition(content)
+	require.NoError(t, err)
+
+	require.Len(t, parsed.Phases, 1)
+	training, ok := parsed.Phases[0].(*kypo.TrainingPhase)
+	require.True(t, ok)
+	assert.Equal(t, kypo.PhaseTypeTraining, training.PhaseType)
+	assert.Equal(t, "answer", training.Tasks[0].Answer)
+	assert.Equal(t, float64(1), training.DecisionMatrix[0].AssessmentAnswered)
+}
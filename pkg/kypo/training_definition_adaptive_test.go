@@ -275,3 +275,83 @@ func TestDeleteTrainingDefinitionAdaptiveServerError(t *testing.T) {
 
 	assert.Equal(t, expected, actual)
 }
+
+func TestDeleteTrainingDefinitionAdaptiveReleased(t *testing.T) {
+	state := kypo.TrainingDefinitionStateReleased
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch request.URL.Path {
+		case "/kypo-adaptive-training/api/v1/training-instances":
+			_, _ = fmt.Fprint(writer, `{"content": [{"id": 3, "training_definition": {"id": 1}}], "pagination": {"number": 0,
+				"number_of_elements": 1, "size": 100, "total_elements": 1, "total_pages": 1}}`)
+		case "/kypo-adaptive-training/api/v1/training-definitions/1/states/UNRELEASED":
+			state = kypo.TrainingDefinitionStateUnreleased
+			writer.WriteHeader(http.StatusNoContent)
+		case "/kypo-adaptive-training/api/v1/training-definitions/1":
+			if request.Method == http.MethodDelete {
+				if state == kypo.TrainingDefinitionStateReleased {
+					writer.WriteHeader(http.StatusConflict)
+				}
+				return
+			}
+			_, _ = fmt.Fprintf(writer, `{"id": 1, "title": "title", "state": "%s"}`, state)
+		default:
+			t.Errorf("unexpected path %s", request.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual := c.DeleteTrainingDefinitionAdaptive(context.Background(), 1)
+
+	assert.EqualError(t, actual, "resource training definition adaptive 1: training definition is released and used by training instances 3")
+	assert.ErrorIs(t, actual, kypo.ErrTrainingDefinitionReleased)
+	assert.Equal(t, kypo.TrainingDefinitionStateReleased, state)
+}
+
+func TestSetTrainingDefinitionAdaptiveStateSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-adaptive-training/api/v1/training-definitions/1/states/UNRELEASED", request.URL.Path)
+		assert.Equal(t, http.MethodPut, request.Method)
+
+		writer.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	err := c.SetTrainingDefinitionAdaptiveState(context.Background(), 1, kypo.TrainingDefinitionStateUnreleased)
+
+	assert.NoError(t, err)
+}
+
+func TestListTrainingDefinitionsAdaptiveSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-adaptive-training/api/v1/training-definitions", request.URL.Path)
+		assert.Equal(t, "UNRELEASED", request.URL.Query().Get("state"))
+
+		_, _ = fmt.Fprint(writer, `{"content": [{"id": 3, "title": "adaptive", "state": "UNRELEASED", "last_edited": null}],
+			"pagination": {"number": 0, "number_of_elements": 1, "size": 20, "total_elements": 1, "total_pages": 1}}`)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := kypo.Pagination[[]kypo.TrainingDefinitionSummary]{
+		Page:       1,
+		PageSize:   20,
+		PageCount:  1,
+		Count:      1,
+		TotalCount: 1,
+		Results: []kypo.TrainingDefinitionSummary{
+			{Id: 3, Title: "adaptive", State: kypo.TrainingDefinitionStateUnreleased, Authors: []kypo.User{}},
+		},
+	}
+
+	actual, err := c.ListTrainingDefinitionsAdaptive(context.Background(), kypo.ListTrainingDefinitionsOptions{
+		State: kypo.TrainingDefinitionStateUnreleased,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expected, actual)
+}
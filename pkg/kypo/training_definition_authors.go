@@ -0,0 +1,90 @@
+package kypo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ListTrainingDefinitionAuthors lists the authors of the definition given by definitionID.
+// Only the authors can edit the definition.
+func (c *Client) ListTrainingDefinitionAuthors(ctx context.Context, definitionID int64) ([]User, error) {
+	return c.listTrainingDefinitionAuthors(ctx, "kypo-rest-training", definitionID)
+}
+
+// AddTrainingDefinitionAuthors adds the users given by userIDs to the authors of the definition given by definitionID.
+func (c *Client) AddTrainingDefinitionAuthors(ctx context.Context, definitionID int64, userIDs ...int64) error {
+	return c.editTrainingDefinitionAuthors(ctx, "kypo-rest-training", definitionID, "authorsAddition", userIDs)
+}
+
+// RemoveTrainingDefinitionAuthors removes the users given by userIDs from the authors of the definition given by definitionID.
+func (c *Client) RemoveTrainingDefinitionAuthors(ctx context.Context, definitionID int64, userIDs ...int64) error {
+	return c.editTrainingDefinitionAuthors(ctx, "kypo-rest-training", definitionID, "authorsRemoval", userIDs)
+}
+
+// ListTrainingDefinitionAdaptiveAuthors lists the authors of the adaptive definition given by definitionID.
+// Only the authors can edit the definition.
+func (c *Client) ListTrainingDefinitionAdaptiveAuthors(ctx context.Context, definitionID int64) ([]User, error) {
+	return c.listTrainingDefinitionAuthors(ctx, "kypo-adaptive-training", definitionID)
+}
+
+// AddTrainingDefinitionAdaptiveAuthors adds the users given by userIDs to the authors of the adaptive definition
+// given by definitionID.
+func (c *Client) AddTrainingDefinitionAdaptiveAuthors(ctx context.Context, definitionID int64, userIDs ...int64) error {
+	return c.editTrainingDefinitionAuthors(ctx, "kypo-adaptive-training", definitionID, "authorsAddition", userIDs)
+}
+
+// RemoveTrainingDefinitionAdaptiveAuthors removes the users given by userIDs from the authors of the adaptive definition
+// given by definitionID.
+func (c *Client) RemoveTrainingDefinitionAdaptiveAuthors(ctx context.Context, definitionID int64, userIDs ...int64) error {
+	return c.editTrainingDefinitionAuthors(ctx, "kypo-adaptive-training", definitionID, "authorsRemoval", userIDs)
+}
+
+func (c *Client) listTrainingDefinitionAuthors(ctx context.Context, service string, definitionID int64) ([]User, error) {
+	authorsURL := fmt.Sprintf("%s/%s/api/v1/training-definitions/%d/authors", c.Endpoint, service, definitionID)
+	authors, err := getAllJavaPages[userRef](ctx, c, authorsURL, "training definition authors", definitionID)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]User, 0, len(authors))
+	for _, author := range authors {
+		users = append(users, author.toUser())
+	}
+	return users, nil
+}
+
+func (c *Client) editTrainingDefinitionAuthors(ctx context.Context, service string, definitionID int64, parameter string, userIDs []int64) error {
+	authorsURL := fmt.Sprintf("%s/%s/api/v1/training-definitions/%d/authors", c.Endpoint, service, definitionID)
+	return c.editUserRefs(ctx, authorsURL, parameter, userIDs, "training definition authors", definitionID)
+}
+
+// editUserRefs adds or removes the users given by userIDs from a list of users at `usersURL` of a training service.
+// The `parameter` selects whether the users are added or removed.
+func (c *Client) editUserRefs(ctx context.Context, usersURL, parameter string, userIDs []int64, resourceName string, identifier any) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		ids = append(ids, strconv.FormatInt(id, 10))
+	}
+	query := url.Values{}
+	query.Set(parameter, strings.Join(ids, ","))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, usersURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = c.doRequestWithRetry(req, http.StatusNoContent, resourceName, identifier)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
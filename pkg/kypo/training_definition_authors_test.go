@@ -0,0 +1,146 @@
+package kypo_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListTrainingDefinitionAuthorsSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+		assert.Equal(t, "/kypo-rest-training/api/v1/training-definitions/1/authors", request.URL.Path)
+		assert.Equal(t, http.MethodGet, request.Method)
+		assert.Equal(t, "100", request.URL.Query().Get("size"))
+
+		switch request.URL.Query().Get("page") {
+		case "0":
+			_, _ = fmt.Fprint(writer, `{"content": [{"user_ref_id": 1, "sub": "kypo-admin", "full_name": "Admin", "given_name": "A", "family_name": "Dmin"}],
+				"pagination": {"number": 0, "number_of_elements": 1, "size": 100, "total_elements": 2, "total_pages": 2}}`)
+		case "1":
+			_, _ = fmt.Fprint(writer, `{"content": [{"user_ref_id": 2, "sub": "assistant", "full_name": "Assistant"}],
+				"pagination": {"number": 1, "number_of_elements": 1, "size": 100, "total_elements": 2, "total_pages": 2}}`)
+		default:
+			t.Errorf("unexpected page %s", request.URL.Query().Get("page"))
+		}
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := []kypo.User{
+		{Id: 1, Sub: "kypo-admin", FullName: "Admin", GivenName: "A", FamilyName: "Dmin"},
+		{Id: 2, Sub: "assistant", FullName: "Assistant"},
+	}
+
+	actual, err := c.ListTrainingDefinitionAuthors(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestListTrainingDefinitionAuthorsNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.Error{
+		ResourceName: "training definition authors",
+		Identifier:   int64(1),
+		Err:          kypo.ErrNotFound,
+	}
+
+	actual, err := c.ListTrainingDefinitionAuthors(context.Background(), 1)
+
+	assert.Nil(t, actual)
+	assert.Equal(t, expected, err)
+}
+
+func TestAddTrainingDefinitionAuthorsSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-rest-training/api/v1/training-definitions/1/authors", request.URL.Path)
+		assert.Equal(t, http.MethodPut, request.Method)
+		assert.Equal(t, "2,3", request.URL.Query().Get("authorsAddition"))
+		assert.Empty(t, request.URL.Query().Get("authorsRemoval"))
+
+		writer.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	err := c.AddTrainingDefinitionAuthors(context.Background(), 1, 2, 3)
+
+	assert.NoError(t, err)
+}
+
+func TestRemoveTrainingDefinitionAuthorsSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-rest-training/api/v1/training-definitions/1/authors", request.URL.Path)
+		assert.Equal(t, "2", request.URL.Query().Get("authorsRemoval"))
+
+		writer.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	err := c.RemoveTrainingDefinitionAuthors(context.Background(), 1, 2)
+
+	assert.NoError(t, err)
+}
+
+func TestRemoveTrainingDefinitionAuthorsEmpty(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		t.Errorf("unexpected request %s %s", request.Method, request.URL.Path)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	err := c.RemoveTrainingDefinitionAuthors(context.Background(), 1)
+
+	assert.NoError(t, err)
+}
+
+func TestAddTrainingDefinitionAdaptiveAuthorsServerError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-adaptive-training/api/v1/training-definitions/1/authors", request.URL.Path)
+		writer.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.Error{
+		ResourceName: "training definition authors",
+		Identifier:   int64(1),
+		Err:          fmt.Errorf("status: 500, body: "),
+	}
+
+	err := c.AddTrainingDefinitionAdaptiveAuthors(context.Background(), 1, 2)
+
+	assert.Equal(t, expected, err)
+}
+
+func TestListTrainingDefinitionAdaptiveAuthorsSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-adaptive-training/api/v1/training-definitions/1/authors", request.URL.Path)
+		_, _ = fmt.Fprint(writer, `{"content": [], "pagination": {"number": 0, "number_of_elements": 0, "size": 100, "total_elements": 0, "total_pages": 0}}`)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.ListTrainingDefinitionAdaptiveAuthors(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Empty(t, actual)
+}
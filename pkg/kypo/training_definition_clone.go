@@ -0,0 +1,122 @@
+package kypo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// errCloneUnsupported is returned by cloneTrainingDefinition when the training service has no clone endpoint.
+var errCloneUnsupported = errors.New("clone endpoint is not supported")
+
+// CloneTrainingDefinition creates a copy of the definition given by definitionID with the given `title`.
+// The copy is unreleased. KYPO's clone endpoint is used if it is available, otherwise the definition
+// is exported, its title and state are rewritten and it is imported again.
+func (c *Client) CloneTrainingDefinition(ctx context.Context, definitionID int64, title string) (*TrainingDefinition, error) {
+	id, err := c.cloneTrainingDefinition(ctx, "kypo-rest-training", "training definition", definitionID, title)
+	if err == nil {
+		return c.GetTrainingDefinition(ctx, id)
+	}
+	if !errors.Is(err, errCloneUnsupported) {
+		return nil, err
+	}
+
+	definition, err := c.GetTrainingDefinition(ctx, definitionID)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := definition.Spec()
+	if err != nil {
+		return nil, err
+	}
+	spec.Title = title
+	spec.State = TrainingDefinitionStateUnreleased
+	content, err := spec.Content()
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateTrainingDefinition(ctx, content)
+}
+
+// CloneTrainingDefinitionAdaptive creates a copy of the adaptive definition given by definitionID with the given `title`.
+// The copy is unreleased. KYPO's clone endpoint is used if it is available, otherwise the definition
+// is exported, its title and state are rewritten and it is imported again.
+func (c *Client) CloneTrainingDefinitionAdaptive(ctx context.Context, definitionID int64, title string) (*TrainingDefinitionAdaptive, error) {
+	id, err := c.cloneTrainingDefinition(ctx, "kypo-adaptive-training", "training definition adaptive", definitionID, title)
+	if err == nil {
+		return c.GetTrainingDefinitionAdaptive(ctx, id)
+	}
+	if !errors.Is(err, errCloneUnsupported) {
+		return nil, err
+	}
+
+	definition, err := c.GetTrainingDefinitionAdaptive(ctx, definitionID)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := definition.Spec()
+	if err != nil {
+		return nil, err
+	}
+	spec.Title = title
+	spec.State = TrainingDefinitionStateUnreleased
+	content, err := spec.Content()
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateTrainingDefinitionAdaptive(ctx, content)
+}
+
+// CloneTrainingDefinitionToInstance copies the definition given by definitionID to the KYPO instance of the `target` client.
+// The content is imported unchanged, so the `sandbox_definition_id` still refers to the sandbox definition
+// of the source instance and may need to be updated.
+func (c *Client) CloneTrainingDefinitionToInstance(ctx context.Context, definitionID int64, target *Client) (*TrainingDefinition, error) {
+	definition, err := c.GetTrainingDefinition(ctx, definitionID)
+	if err != nil {
+		return nil, err
+	}
+	return target.CreateTrainingDefinition(ctx, definition.Content)
+}
+
+// CloneTrainingDefinitionAdaptiveToInstance copies the adaptive definition given by definitionID to the KYPO instance
+// of the `target` client. The content is imported unchanged.
+func (c *Client) CloneTrainingDefinitionAdaptiveToInstance(ctx context.Context, definitionID int64, target *Client) (*TrainingDefinitionAdaptive, error) {
+	definition, err := c.GetTrainingDefinitionAdaptive(ctx, definitionID)
+	if err != nil {
+		return nil, err
+	}
+	return target.CreateTrainingDefinitionAdaptive(ctx, definition.Content)
+}
+
+// cloneTrainingDefinition clones the definition using the clone endpoint of the training `service` and returns the id of the copy.
+func (c *Client) cloneTrainingDefinition(ctx context.Context, service, resourceName string, definitionID int64, title string) (int64, error) {
+	query := url.Values{}
+	query.Set("title", title)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/%s/api/v1/training-definitions/%d?%s", c.Endpoint, service, definitionID, query.Encode()), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	// Services without the clone endpoint respond with 405, which is not retried so that the fallback starts right away
+	body, statusCode, err := c.doRequestWithRetry(req, http.StatusOK, resourceName, definitionID, http.StatusMethodNotAllowed)
+	if statusCode == http.StatusMethodNotAllowed {
+		return 0, errCloneUnsupported
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	id := struct {
+		Id int64 `json:"id"`
+	}{}
+
+	err = json.Unmarshal(body, &id)
+	if err != nil {
+		return 0, err
+	}
+
+	return id.Id, nil
+}
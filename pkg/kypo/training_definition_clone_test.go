@@ -0,0 +1,171 @@
+package kypo_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCloneTrainingDefinitionSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+		if request.Method == http.MethodPost {
+			assert.Equal(t, "/kypo-rest-training/api/v1/training-definitions/1", request.URL.Path)
+			assert.Equal(t, "copy of title", request.URL.Query().Get("title"))
+			_, _ = fmt.Fprint(writer, `{"id": 2, "title": "copy of title", "state": "UNRELEASED"}`)
+			return
+		}
+		assert.Equal(t, http.MethodGet, request.Method)
+		assert.Equal(t, "/kypo-rest-training/api/v1/exports/training-definitions/2", request.URL.Path)
+		_, _ = fmt.Fprint(writer, trainingDefinitionJsonString)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := kypo.TrainingDefinition{
+		Id:      2,
+		Content: trainingDefinitionJsonString,
+	}
+
+	actual, err := c.CloneTrainingDefinition(context.Background(), 1, "copy of title")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expected, actual)
+}
+
+func TestCloneTrainingDefinitionFallback(t *testing.T) {
+	var imported string
+	clones := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch request.Method + " " + request.URL.Path {
+		case "POST /kypo-rest-training/api/v1/training-definitions/1":
+			clones++
+			writer.WriteHeader(http.StatusMethodNotAllowed)
+		case "GET /kypo-rest-training/api/v1/exports/training-definitions/1":
+			_, _ = fmt.Fprint(writer, `{"title": "title", "state": "RELEASED", "levels": [], "estimated_duration": 15}`)
+		case "POST /kypo-rest-training/api/v1/imports/training-definitions":
+			body, _ := io.ReadAll(request.Body)
+			imported = string(body)
+			_, _ = fmt.Fprint(writer, `{"id": 3}`)
+		default:
+			t.Errorf("unexpected request %s %s", request.Method, request.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+	c.RetryCount = 3
+
+	actual, err := c.CloneTrainingDefinition(context.Background(), 1, "copy of title")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), actual.Id)
+	assert.JSONEq(t, `{"title": "copy of title", "state": "UNRELEASED", "levels": [], "estimated_duration": 15}`, imported)
+	assert.Equal(t, imported, actual.Content)
+	assert.Equal(t, 1, clones)
+}
+
+func TestCloneTrainingDefinitionNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.Error{
+		ResourceName: "training definition",
+		Identifier:   int64(1),
+		Err:          kypo.ErrNotFound,
+	}
+
+	actual, err := c.CloneTrainingDefinition(context.Background(), 1, "copy of title")
+
+	assert.Nil(t, actual)
+	assert.Equal(t, expected, err)
+}
+
+func TestCloneTrainingDefinitionAdaptiveFallback(t *testing.T) {
+	var imported string
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch request.Method + " " + request.URL.Path {
+		case "POST /kypo-adaptive-training/api/v1/training-definitions/1":
+			writer.WriteHeader(http.StatusMethodNotAllowed)
+		case "GET /kypo-adaptive-training/api/v1/exports/training-definitions/1":
+			_, _ = fmt.Fprint(writer, trainingDefinitionAdaptiveJsonString)
+		case "POST /kypo-adaptive-training/api/v1/imports/training-definitions":
+			body, _ := io.ReadAll(request.Body)
+			imported = string(body)
+			_, _ = fmt.Fprint(writer, `{"id": 3}`)
+		default:
+			t.Errorf("unexpected request %s %s", request.Method, request.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.CloneTrainingDefinitionAdaptive(context.Background(), 1, "copy of title")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), actual.Id)
+	assert.JSONEq(t, `{"title":"copy of title","description":"description","prerequisites":[],"outcomes":[],"state":"UNRELEASED","show_stepper_bar":true,"phases":[],"estimated_duration":0}`, imported)
+}
+
+func TestCloneTrainingDefinitionToInstance(t *testing.T) {
+	source := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertTrainingDefinitionGet(t, request)
+		_, _ = fmt.Fprint(writer, trainingDefinitionJsonString)
+	}))
+	defer source.Close()
+
+	target := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertTrainingDefinitionCreate(t, request)
+		body, _ := io.ReadAll(request.Body)
+		assert.Equal(t, trainingDefinitionJsonString, string(body))
+		_, _ = fmt.Fprint(writer, `{"id": 7}`)
+	}))
+	defer target.Close()
+
+	c := minimalClient(source)
+	targetClient := minimalClient(target)
+
+	expected := kypo.TrainingDefinition{
+		Id:      7,
+		Content: trainingDefinitionJsonString,
+	}
+
+	actual, err := c.CloneTrainingDefinitionToInstance(context.Background(), 1, &targetClient)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expected, actual)
+}
+
+func TestCloneTrainingDefinitionAdaptiveToInstance(t *testing.T) {
+	source := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-adaptive-training/api/v1/exports/training-definitions/1", request.URL.Path)
+		_, _ = fmt.Fprint(writer, trainingDefinitionAdaptiveJsonString)
+	}))
+	defer source.Close()
+
+	target := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-adaptive-training/api/v1/imports/training-definitions", request.URL.Path)
+		_, _ = fmt.Fprint(writer, `{"id": 7}`)
+	}))
+	defer target.Close()
+
+	c := minimalClient(source)
+	targetClient := minimalClient(target)
+
+	actual, err := c.CloneTrainingDefinitionAdaptiveToInstance(context.Background(), 1, &targetClient)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &kypo.TrainingDefinitionAdaptive{Id: 7, Content: trainingDefinitionAdaptiveJsonString}, actual)
+}
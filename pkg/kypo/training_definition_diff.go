@@ -0,0 +1,202 @@
+package kypo
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ChangeKind is the kind of a change between two training definitions.
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"
+	ChangeRemoved  ChangeKind = "removed"
+	ChangeModified ChangeKind = "modified"
+)
+
+// Change is a single difference between two training definitions found by DiffTrainingDefinitions.
+// The Path addresses the changed field, for example `levels[2].hints[0].content`.
+// Old is nil for added fields and New is nil for removed fields.
+type Change struct {
+	Path string
+	Kind ChangeKind
+	Old  any
+	New  any
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("%s: added %s", c.Path, formatChangeValue(c.New))
+	case ChangeRemoved:
+		return fmt.Sprintf("%s: removed %s", c.Path, formatChangeValue(c.Old))
+	default:
+		return fmt.Sprintf("%s: changed from %s to %s", c.Path, formatChangeValue(c.Old), formatChangeValue(c.New))
+	}
+}
+
+// ignoredDiffFields are generated by KYPO and differ between exports of otherwise equal training definitions.
+var ignoredDiffFields = map[string]bool{
+	"id":             true,
+	"created":        true,
+	"creation_time":  true,
+	"last_edited":    true,
+	"last_edited_by": true,
+}
+
+// DiffTrainingDefinitions compares the content of two linear or adaptive training definitions and returns
+// the changes needed to turn `a` into `b`. Ids, timestamps and the order of object keys are ignored.
+// Levels and phases are compared by their position.
+func DiffTrainingDefinitions(a, b string) ([]Change, error) {
+	normalizedA, err := normalizeDefinitionContent(a)
+	if err != nil {
+		return nil, err
+	}
+	normalizedB, err := normalizeDefinitionContent(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	diffValues(&changes, "", normalizedA, normalizedB)
+	return changes, nil
+}
+
+// EqualContent reports whether the content of two linear or adaptive training definitions is equal
+// when ids, timestamps and the order of object keys are ignored. It can be used to detect drift.
+func EqualContent(a, b string) (bool, error) {
+	changes, err := DiffTrainingDefinitions(a, b)
+	if err != nil {
+		return false, err
+	}
+	return len(changes) == 0, nil
+}
+
+func normalizeDefinitionContent(content string) (any, error) {
+	var value any
+	err := json.Unmarshal([]byte(content), &value)
+	if err != nil {
+		return nil, err
+	}
+	return normalizeValue(value), nil
+}
+
+func normalizeValue(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		normalized := make(map[string]any, len(value))
+		for key, field := range value {
+			if ignoredDiffFields[key] {
+				continue
+			}
+			normalized[key] = normalizeValue(field)
+		}
+		return normalized
+	case []any:
+		normalized := make([]any, 0, len(value))
+		for _, item := range value {
+			normalized = append(normalized, normalizeValue(item))
+		}
+		return normalized
+	default:
+		return value
+	}
+}
+
+func diffValues(changes *[]Change, path string, a, b any) {
+	if a == nil && isEmptyValue(b) || b == nil && isEmptyValue(a) {
+		return
+	}
+
+	switch a := a.(type) {
+	case map[string]any:
+		if b, ok := b.(map[string]any); ok {
+			diffObjects(changes, path, a, b)
+			return
+		}
+	case []any:
+		if b, ok := b.([]any); ok {
+			diffArrays(changes, path, a, b)
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*changes = append(*changes, Change{Path: path, Kind: ChangeModified, Old: a, New: b})
+	}
+}
+
+func diffObjects(changes *[]Change, path string, a, b map[string]any) {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fieldPath := key
+		if path != "" {
+			fieldPath = path + "." + key
+		}
+		valueA, okA := a[key]
+		valueB, okB := b[key]
+		switch {
+		case !okA && !isEmptyValue(valueB):
+			*changes = append(*changes, Change{Path: fieldPath, Kind: ChangeAdded, New: valueB})
+		case !okB && !isEmptyValue(valueA):
+			*changes = append(*changes, Change{Path: fieldPath, Kind: ChangeRemoved, Old: valueA})
+		case okA && okB:
+			diffValues(changes, fieldPath, valueA, valueB)
+		}
+	}
+}
+
+func diffArrays(changes *[]Change, path string, a, b []any) {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		switch {
+		case i >= len(a):
+			*changes = append(*changes, Change{Path: itemPath, Kind: ChangeAdded, New: b[i]})
+		case i >= len(b):
+			*changes = append(*changes, Change{Path: itemPath, Kind: ChangeRemoved, Old: a[i]})
+		default:
+			diffValues(changes, itemPath, a[i], b[i])
+		}
+	}
+}
+
+// isEmptyValue reports whether the JSON value is null or an empty array or object, which KYPO uses interchangeably.
+func isEmptyValue(value any) bool {
+	switch value := value.(type) {
+	case nil:
+		return true
+	case []any:
+		return len(value) == 0
+	case map[string]any:
+		return len(value) == 0
+	}
+	return false
+}
+
+func formatChangeValue(value any) string {
+	switch value := value.(type) {
+	case map[string]any:
+		if title, ok := value["title"].(string); ok {
+			return fmt.Sprintf("%q", title)
+		}
+	case string:
+		return fmt.Sprintf("%q", value)
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
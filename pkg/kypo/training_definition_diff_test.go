@@ -0,0 +1,83 @@
+package kypo_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+	"testing"
+)
+
+func TestDiffTrainingDefinitionsEqual(t *testing.T) {
+	a := `{"title": "title", "state": "UNRELEASED", "levels": [{"title": "info", "level_type": "INFO_LEVEL", "content": "content", "order": 0}]}`
+	b := `{"levels": [{"id": 15, "order": 0, "content": "content", "level_type": "INFO_LEVEL", "title": "info",
+		"attachments": null}], "state": "UNRELEASED", "title": "title", "last_edited": "2023-11-26T09:03:35.313174494Z"}`
+
+	changes, err := kypo.DiffTrainingDefinitions(a, b)
+
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	equal, err := kypo.EqualContent(a, b)
+
+	assert.NoError(t, err)
+	assert.True(t, equal)
+}
+
+func TestDiffTrainingDefinitions(t *testing.T) {
+	a := readTestdata(t, "training_definition.json")
+	spec, err := kypo.ParseLinearTrainingDefinition(a)
+	require.NoError(t, err)
+
+	spec.Title = "Senior hacker"
+	spec.Levels[2].(*kypo.TrainingLevel).Hints[0].Content = "Use nmap -sV."
+	spec.Levels[3].(*kypo.TrainingLevel).ExpectedCommands = []string{"hydra"}
+	spec.Levels = spec.Levels[:4]
+	b, err := spec.Content()
+	require.NoError(t, err)
+
+	changes, err := kypo.DiffTrainingDefinitions(a, b)
+	require.NoError(t, err)
+
+	expected := []string{
+		`levels[2].hints[0].content: changed from "Use nmap." to "Use nmap -sV."`,
+		`levels[3].expected_commands[0]: added "hydra"`,
+		`levels[4]: removed "Feedback"`,
+		`title: changed from "Junior hacker" to "Senior hacker"`,
+	}
+	actual := make([]string, 0, len(changes))
+	for _, change := range changes {
+		actual = append(actual, change.String())
+	}
+
+	assert.Equal(t, expected, actual)
+	assert.Equal(t, kypo.Change{Path: "title", Kind: kypo.ChangeModified, Old: "Junior hacker", New: "Senior hacker"}, changes[3])
+
+	equal, err := kypo.EqualContent(a, b)
+
+	assert.NoError(t, err)
+	assert.False(t, equal)
+}
+
+func TestDiffTrainingDefinitionsAdaptive(t *testing.T) {
+	a := readTestdata(t, "training_definition_adaptive.json")
+	spec, err := kypo.ParseAdaptiveTrainingDefinition(a)
+	require.NoError(t, err)
+
+	spec.Phases[4].(*kypo.TrainingPhase).DecisionMatrix[1].WrongAnswers = 0.5
+	b, err := spec.Content()
+	require.NoError(t, err)
+
+	changes, err := kypo.DiffTrainingDefinitions(a, b)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []kypo.Change{
+		{Path: "phases[4].decision_matrix[1].wrong_answers", Kind: kypo.ChangeModified, Old: 0.25, New: 0.5},
+	}, changes)
+}
+
+func TestDiffTrainingDefinitionsInvalid(t *testing.T) {
+	changes, err := kypo.DiffTrainingDefinitions(`{}`, `{`)
+
+	assert.Nil(t, changes)
+	assert.Error(t, err)
+}
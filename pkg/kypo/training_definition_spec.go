@@ -0,0 +1,382 @@
+package kypo
+
+import (
+	"encoding/json"
+)
+
+// LinearTrainingDefinitionSpec is the content of an exported linear training definition, see TrainingDefinition.Content.
+// Fields which are not modelled are preserved, so parsing and marshalling the content again is lossless.
+type LinearTrainingDefinitionSpec struct {
+	Title               string                  `json:"title"`
+	Description         string                  `json:"description"`
+	Prerequisites       []string                `json:"prerequisites"`
+	Outcomes            []string                `json:"outcomes"`
+	State               TrainingDefinitionState `json:"state"`
+	ShowStepperBar      bool                    `json:"show_stepper_bar"`
+	SandboxDefinitionId int64                   `json:"sandbox_definition_id"`
+	EstimatedDuration   int64                   `json:"estimated_duration"`
+	VariantSandboxes    bool                    `json:"variant_sandboxes"`
+	Levels              Levels                  `json:"levels"`
+
+	fields map[string]json.RawMessage
+}
+
+// LevelType is the type of level of a linear training definition.
+type LevelType string
+
+const (
+	LevelTypeInfo       LevelType = "INFO_LEVEL"
+	LevelTypeTraining   LevelType = "TRAINING_LEVEL"
+	LevelTypeAssessment LevelType = "ASSESSMENT_LEVEL"
+	LevelTypeAccess     LevelType = "ACCESS_LEVEL"
+)
+
+// Level is a level of a linear training definition. It is one of *InfoLevel, *TrainingLevel, *AssessmentLevel,
+// *AccessLevel or *UnknownLevel for level types not known to this library.
+type Level interface {
+	// Base returns the fields common to all levels.
+	Base() *LevelBase
+}
+
+// Levels is a list of levels which is unmarshalled to the concrete level types based on their `level_type`.
+type Levels []Level
+
+// LevelBase contains the fields common to all levels.
+type LevelBase struct {
+	Title             string    `json:"title"`
+	LevelType         LevelType `json:"level_type"`
+	MaxScore          int64     `json:"max_score"`
+	Order             int64     `json:"order"`
+	EstimatedDuration int64     `json:"estimated_duration"`
+}
+
+type InfoLevel struct {
+	LevelBase
+	Content string `json:"content"`
+
+	fields map[string]json.RawMessage
+}
+
+type TrainingLevel struct {
+	LevelBase
+	Content              string           `json:"content"`
+	Solution             string           `json:"solution"`
+	SolutionPenalized    bool             `json:"solution_penalized"`
+	Answer               string           `json:"answer"`
+	AnswerVariableName   string           `json:"answer_variable_name"`
+	IncorrectAnswerLimit int64            `json:"incorrect_answer_limit"`
+	VariantAnswers       bool             `json:"variant_answers"`
+	Hints                []Hint           `json:"hints"`
+	Attachments          []Attachment     `json:"attachments"`
+	MitreTechniques      []MitreTechnique `json:"mitre_techniques"`
+	ExpectedCommands     []string         `json:"expected_commands"`
+	CommandsRequired     bool             `json:"commands_required"`
+
+	fields map[string]json.RawMessage
+}
+
+type Hint struct {
+	Title       string `json:"title"`
+	Content     string `json:"content"`
+	HintPenalty int64  `json:"hint_penalty"`
+	Order       int64  `json:"order"`
+
+	fields map[string]json.RawMessage
+}
+
+type Attachment struct {
+	Link         string `json:"link"`
+	CreationTime string `json:"creation_time"`
+
+	fields map[string]json.RawMessage
+}
+
+type MitreTechnique struct {
+	TechniqueKey string `json:"technique_key"`
+
+	fields map[string]json.RawMessage
+}
+
+type AccessLevel struct {
+	LevelBase
+	Passkey      string `json:"passkey"`
+	CloudContent string `json:"cloud_content"`
+	LocalContent string `json:"local_content"`
+
+	fields map[string]json.RawMessage
+}
+
+type AssessmentLevel struct {
+	LevelBase
+	Instructions   string               `json:"instructions"`
+	AssessmentType string               `json:"assessment_type"`
+	Questions      []AssessmentQuestion `json:"questions"`
+
+	fields map[string]json.RawMessage
+}
+
+type AssessmentQuestion struct {
+	QuestionType               string                      `json:"question_type"`
+	Text                       string                      `json:"text"`
+	Points                     int64                       `json:"points"`
+	Penalty                    int64                       `json:"penalty"`
+	Order                      int64                       `json:"order"`
+	AnswerRequired             bool                        `json:"answer_required"`
+	Choices                    []AssessmentChoice          `json:"choices"`
+	ExtendedMatchingOptions    []ExtendedMatchingOption    `json:"extended_matching_options"`
+	ExtendedMatchingStatements []ExtendedMatchingStatement `json:"extended_matching_statements"`
+
+	fields map[string]json.RawMessage
+}
+
+type AssessmentChoice struct {
+	Text    string `json:"text"`
+	Correct bool   `json:"correct"`
+	Order   int64  `json:"order"`
+
+	fields map[string]json.RawMessage
+}
+
+type ExtendedMatchingOption struct {
+	Text  string `json:"text"`
+	Order int64  `json:"order"`
+
+	fields map[string]json.RawMessage
+}
+
+type ExtendedMatchingStatement struct {
+	Text               string `json:"text"`
+	Order              int64  `json:"order"`
+	CorrectOptionOrder int64  `json:"correct_option_order"`
+
+	fields map[string]json.RawMessage
+}
+
+// UnknownLevel is a level of a type which is not known to this library. All its fields are preserved.
+type UnknownLevel struct {
+	LevelBase
+
+	fields map[string]json.RawMessage
+}
+
+// ParseLinearTrainingDefinition parses the content of an exported linear training definition.
+func ParseLinearTrainingDefinition(content string) (*LinearTrainingDefinitionSpec, error) {
+	spec := LinearTrainingDefinitionSpec{}
+	err := json.Unmarshal([]byte(content), &spec)
+	if err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// Spec parses the Content of the training definition.
+func (d *TrainingDefinition) Spec() (*LinearTrainingDefinitionSpec, error) {
+	return ParseLinearTrainingDefinition(d.Content)
+}
+
+// Content marshals the spec to a JSON string, which can be imported by CreateTrainingDefinition.
+func (s *LinearTrainingDefinitionSpec) Content() (string, error) {
+	content, err := marshalJSON(s)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+func (l *Levels) UnmarshalJSON(data []byte) error {
+	var rawLevels []json.RawMessage
+	err := json.Unmarshal(data, &rawLevels)
+	if err != nil {
+		return err
+	}
+	if rawLevels == nil {
+		*l = nil
+		return nil
+	}
+
+	levels := make(Levels, 0, len(rawLevels))
+	for _, rawLevel := range rawLevels {
+		levelType := struct {
+			LevelType LevelType `json:"level_type"`
+		}{}
+		err = json.Unmarshal(rawLevel, &levelType)
+		if err != nil {
+			return err
+		}
+
+		var level Level
+		switch levelType.LevelType {
+		case LevelTypeInfo:
+			level = &InfoLevel{}
+		case LevelTypeTraining:
+			level = &TrainingLevel{}
+		case LevelTypeAssessment:
+			level = &AssessmentLevel{}
+		case LevelTypeAccess:
+			level = &AccessLevel{}
+		default:
+			level = &UnknownLevel{}
+		}
+		err = json.Unmarshal(rawLevel, level)
+		if err != nil {
+			return err
+		}
+		levels = append(levels, level)
+	}
+
+	*l = levels
+	return nil
+}
+
+func (l *InfoLevel) Base() *LevelBase       { return &l.LevelBase }
+func (l *TrainingLevel) Base() *LevelBase   { return &l.LevelBase }
+func (l *AssessmentLevel) Base() *LevelBase { return &l.LevelBase }
+func (l *AccessLevel) Base() *LevelBase     { return &l.LevelBase }
+func (l *UnknownLevel) Base() *LevelBase    { return &l.LevelBase }
+
+func (s *LinearTrainingDefinitionSpec) UnmarshalJSON(data []byte) (err error) {
+	type plain LinearTrainingDefinitionSpec
+	s.fields, err = unmarshalObject(data, (*plain)(s))
+	return
+}
+
+func (s LinearTrainingDefinitionSpec) MarshalJSON() ([]byte, error) {
+	type plain LinearTrainingDefinitionSpec
+	return marshalObject(plain(s), s.fields)
+}
+
+func (l *InfoLevel) UnmarshalJSON(data []byte) (err error) {
+	type plain InfoLevel
+	l.fields, err = unmarshalObject(data, (*plain)(l))
+	return
+}
+
+func (l InfoLevel) MarshalJSON() ([]byte, error) {
+	type plain InfoLevel
+	l.LevelType = LevelTypeInfo
+	return marshalObject(plain(l), l.fields)
+}
+
+func (l *TrainingLevel) UnmarshalJSON(data []byte) (err error) {
+	type plain TrainingLevel
+	l.fields, err = unmarshalObject(data, (*plain)(l))
+	return
+}
+
+func (l TrainingLevel) MarshalJSON() ([]byte, error) {
+	type plain TrainingLevel
+	l.LevelType = LevelTypeTraining
+	return marshalObject(plain(l), l.fields)
+}
+
+func (l *AssessmentLevel) UnmarshalJSON(data []byte) (err error) {
+	type plain AssessmentLevel
+	l.fields, err = unmarshalObject(data, (*plain)(l))
+	return
+}
+
+func (l AssessmentLevel) MarshalJSON() ([]byte, error) {
+	type plain AssessmentLevel
+	l.LevelType = LevelTypeAssessment
+	return marshalObject(plain(l), l.fields)
+}
+
+func (l *AccessLevel) UnmarshalJSON(data []byte) (err error) {
+	type plain AccessLevel
+	l.fields, err = unmarshalObject(data, (*plain)(l))
+	return
+}
+
+func (l AccessLevel) MarshalJSON() ([]byte, error) {
+	type plain AccessLevel
+	l.LevelType = LevelTypeAccess
+	return marshalObject(plain(l), l.fields)
+}
+
+func (l *UnknownLevel) UnmarshalJSON(data []byte) (err error) {
+	type plain UnknownLevel
+	l.fields, err = unmarshalObject(data, (*plain)(l))
+	return
+}
+
+func (l UnknownLevel) MarshalJSON() ([]byte, error) {
+	type plain UnknownLevel
+	return marshalObject(plain(l), l.fields)
+}
+
+func (h *Hint) UnmarshalJSON(data []byte) (err error) {
+	type plain Hint
+	h.fields, err = unmarshalObject(data, (*plain)(h))
+	return
+}
+
+func (h Hint) MarshalJSON() ([]byte, error) {
+	type plain Hint
+	return marshalObject(plain(h), h.fields)
+}
+
+func (a *Attachment) UnmarshalJSON(data []byte) (err error) {
+	type plain Attachment
+	a.fields, err = unmarshalObject(data, (*plain)(a))
+	return
+}
+
+func (a Attachment) MarshalJSON() ([]byte, error) {
+	type plain Attachment
+	return marshalObject(plain(a), a.fields)
+}
+
+func (m *MitreTechnique) UnmarshalJSON(data []byte) (err error) {
+	type plain MitreTechnique
+	m.fields, err = unmarshalObject(data, (*plain)(m))
+	return
+}
+
+func (m MitreTechnique) MarshalJSON() ([]byte, error) {
+	type plain MitreTechnique
+	return marshalObject(plain(m), m.fields)
+}
+
+func (q *AssessmentQuestion) UnmarshalJSON(data []byte) (err error) {
+	type plain AssessmentQuestion
+	q.fields, err = unmarshalObject(data, (*plain)(q))
+	return
+}
+
+func (q AssessmentQuestion) MarshalJSON() ([]byte, error) {
+	type plain AssessmentQuestion
+	return marshalObject(plain(q), q.fields)
+}
+
+func (c *AssessmentChoice) UnmarshalJSON(data []byte) (err error) {
+	type plain AssessmentChoice
+	c.fields, err = unmarshalObject(data, (*plain)(c))
+	return
+}
+
+func (c AssessmentChoice) MarshalJSON() ([]byte, error) {
+	type plain AssessmentChoice
+	return marshalObject(plain(c), c.fields)
+}
+
+func (o *ExtendedMatchingOption) UnmarshalJSON(data []byte) (err error) {
+	type plain ExtendedMatchingOption
+	o.fields, err = unmarshalObject(data, (*plain)(o))
+	return
+}
+
+func (o ExtendedMatchingOption) MarshalJSON() ([]byte, error) {
+	type plain ExtendedMatchingOption
+	return marshalObject(plain(o), o.fields)
+}
+
+func (s *ExtendedMatchingStatement) UnmarshalJSON(data []byte) (err error) {
+	type plain ExtendedMatchingStatement
+	s.fields, err = unmarshalObject(data, (*plain)(s))
+	return
+}
+
+func (s ExtendedMatchingStatement) MarshalJSON() ([]byte, error) {
+	type plain ExtendedMatchingStatement
+	return marshalObject(plain(s), s.fields)
+}
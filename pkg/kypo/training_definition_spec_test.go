@@ -0,0 +1,134 @@
+package kypo_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+	"os"
+	"testing"
+)
+
+func readTestdata(t *testing.T, name string) string {
+	content, err := os.ReadFile("testdata/" + name)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestParseLinearTrainingDefinition(t *testing.T) {
+	spec, err := kypo.ParseLinearTrainingDefinition(readTestdata(t, "training_definition.json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Junior hacker", spec.Title)
+	assert.Equal(t, []string{"Basic Linux command line"}, spec.Prerequisites)
+	assert.Equal(t, int64(42), spec.EstimatedDuration)
+	assert.True(t, spec.VariantSandboxes)
+	require.Len(t, spec.Levels, 5)
+
+	info, ok := spec.Levels[0].(*kypo.InfoLevel)
+	require.True(t, ok)
+	assert.Equal(t, "Introduction", info.Title)
+	assert.Equal(t, "Welcome to the <b>Junior hacker</b> training & good luck.", info.Content)
+
+	access, ok := spec.Levels[1].(*kypo.AccessLevel)
+	require.True(t, ok)
+	assert.Equal(t, "start-training", access.Passkey)
+
+	training, ok := spec.Levels[2].(*kypo.TrainingLevel)
+	require.True(t, ok)
+	assert.Equal(t, kypo.LevelTypeTraining, training.LevelType)
+	assert.Equal(t, int64(20), training.MaxScore)
+	assert.Equal(t, "22", training.Answer)
+	assert.Len(t, training.Hints, 2)
+	assert.Equal(t, int64(5), training.Hints[1].HintPenalty)
+	assert.Equal(t, "https://nmap.org/book/man.html", training.Attachments[0].Link)
+	assert.Equal(t, "T1046", training.MitreTechniques[0].TechniqueKey)
+
+	variant, ok := spec.Levels[3].(*kypo.TrainingLevel)
+	require.True(t, ok)
+	assert.Equal(t, "", variant.Answer)
+	assert.Equal(t, "password", variant.AnswerVariableName)
+
+	assessment, ok := spec.Levels[4].(*kypo.AssessmentLevel)
+	require.True(t, ok)
+	assert.Equal(t, "TEST", assessment.AssessmentType)
+	require.Len(t, assessment.Questions, 3)
+	assert.True(t, assessment.Questions[0].Choices[0].Correct)
+	assert.Equal(t, int64(1), assessment.Questions[2].ExtendedMatchingStatements[1].CorrectOptionOrder)
+
+	for i, level := range spec.Levels {
+		assert.Equal(t, int64(i), level.Base().Order)
+	}
+}
+
+func TestLinearTrainingDefinitionRoundTrip(t *testing.T) {
+	content := readTestdata(t, "training_definition.json")
+
+	spec, err := kypo.ParseLinearTrainingDefinition(content)
+	require.NoError(t, err)
+
+	actual, err := spec.Content()
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, content, actual)
+	assert.Contains(t, actual, "<b>Junior hacker</b> training & good luck.")
+}
+
+func TestLinearTrainingDefinitionRoundTripMinimal(t *testing.T) {
+	definition := kypo.TrainingDefinition{Id: 1, Content: trainingDefinitionJsonString}
+
+	spec, err := definition.Spec()
+	require.NoError(t, err)
+
+	actual, err := spec.Content()
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, trainingDefinitionJsonString, actual)
+}
+
+func TestLinearTrainingDefinitionModify(t *testing.T) {
+	spec, err := kypo.ParseLinearTrainingDefinition(readTestdata(t, "training_definition.json"))
+	require.NoError(t, err)
+
+	spec.Title = "Senior hacker"
+	spec.Levels[2].(*kypo.TrainingLevel).Answer = "2222"
+	spec.Levels = append(spec.Levels[:1], spec.Levels[2:]...)
+
+	content, err := spec.Content()
+	require.NoError(t, err)
+
+	modified, err := kypo.ParseLinearTrainingDefinition(content)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Senior hacker", modified.Title)
+	require.Len(t, modified.Levels, 4)
+	assert.Equal(t, "2222", modified.Levels[1].(*kypo.TrainingLevel).Answer)
+	assert.Equal(t, "password", modified.Levels[2].(*kypo.TrainingLevel).AnswerVariableName)
+}
+
+func TestLinearTrainingDefinitionNew(t *testing.T) {
+	spec := kypo.LinearTrainingDefinitionSpec{
+		Title: "title",
+		State: kypo.TrainingDefinitionStateUnreleased,
+		Levels: kypo.Levels{
+			&kypo.InfoLevel{LevelBase: kypo.LevelBase{Title: "info"}, Content: "content"},
+			&kypo.UnknownLevel{LevelBase: kypo.LevelBase{Title: "unknown", LevelType: "CUSTOM_LEVEL", Order: 1}},
+		},
+	}
+
+	content, err := spec.Content()
+	require.NoError(t, err)
+
+	parsed, err := kypo.ParseLinearTrainingDefinition(content)
+	require.NoError(t, err)
+
+	assert.Equal(t, kypo.LevelTypeInfo, parsed.Levels[0].Base().LevelType)
+	assert.IsType(t, &kypo.UnknownLevel{}, parsed.Levels[1])
+	assert.Equal(t, kypo.LevelType("CUSTOM_LEVEL"), parsed.Levels[1].Base().LevelType)
+}
+
+func TestParseLinearTrainingDefinitionInvalid(t *testing.T) {
+	spec, err := kypo.ParseLinearTrainingDefinition(`{"title": "title", "levels": {}}`)
+
+	assert.Nil(t, spec)
+	assert.Error(t, err)
+}
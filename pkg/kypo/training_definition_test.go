@@ -8,7 +8,9 @@ import (
 	"github.com/vydrazde/kypo-go-client/pkg/kypo"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 var trainingDefinitionJsonString = `{"title":"title","description":"description","prerequisites":[],"outcomes":[],"state":"UNRELEASED","show_stepper_bar":true,"levels":[],"estimated_duration":0,"variant_sandboxes":false}`
@@ -286,3 +288,262 @@ func TestDeleteTrainingDefinitionServerError(t *testing.T) {
 
 	assert.Equal(t, expected, actual)
 }
+
+// releasedTrainingDefinitionServer refuses to delete the training definition 1 while it is released
+// and lists the training instances with the given training definition ids.
+func releasedTrainingDefinitionServer(t *testing.T, deletes *int, definitionIDs ...int64) *httptest.Server {
+	state := kypo.TrainingDefinitionStateReleased
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch request.URL.Path {
+		case "/kypo-rest-training/api/v1/training-definitions/1":
+			if request.Method == http.MethodDelete {
+				*deletes++
+				if state == kypo.TrainingDefinitionStateReleased {
+					writer.WriteHeader(http.StatusConflict)
+					_, _ = fmt.Fprint(writer, `{"status": "CONFLICT", "message": "Cannot delete released training definition."}`)
+				}
+				return
+			}
+			_, _ = fmt.Fprintf(writer, `{"id": 1, "title": "title", "state": "%s"}`, state)
+		case "/kypo-rest-training/api/v1/training-definitions/1/states/UNRELEASED":
+			assert.Equal(t, http.MethodPut, request.Method)
+			state = kypo.TrainingDefinitionStateUnreleased
+			writer.WriteHeader(http.StatusNoContent)
+		case "/kypo-rest-training/api/v1/training-instances":
+			var instances []string
+			for i, definitionID := range definitionIDs {
+				instances = append(instances, fmt.Sprintf(`{"id": %d, "training_definition": {"id": %d}}`, i+1, definitionID))
+			}
+			_, _ = fmt.Fprintf(writer, `{"content": [%s], "pagination": {"number": 0, "number_of_elements": %d, "size": 100,
+				"total_elements": %d, "total_pages": 1}}`, strings.Join(instances, ","), len(instances), len(instances))
+		default:
+			t.Errorf("unexpected path %s", request.URL.Path)
+		}
+	}))
+}
+
+func TestDeleteTrainingDefinitionReleased(t *testing.T) {
+	deletes := 0
+	ts := releasedTrainingDefinitionServer(t, &deletes, 2)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+	c.RetryCount = 2
+
+	actual := c.DeleteTrainingDefinition(context.Background(), 1)
+
+	assert.NoError(t, actual)
+	assert.Equal(t, 2, deletes)
+}
+
+func TestDeleteTrainingDefinitionReleasedWithInstances(t *testing.T) {
+	deletes := 0
+	ts := releasedTrainingDefinitionServer(t, &deletes, 1, 2, 1)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	c.RetryCount = 2
+
+	actual := c.DeleteTrainingDefinition(context.Background(), 1)
+
+	assert.EqualError(t, actual, "resource training definition 1: training definition is released and used by training instances 1, 3")
+	assert.ErrorIs(t, actual, kypo.ErrTrainingDefinitionReleased)
+	assert.Equal(t, 1, deletes)
+}
+
+func TestDeleteTrainingDefinitionConflictUnreleased(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method == http.MethodDelete {
+			writer.WriteHeader(http.StatusConflict)
+			return
+		}
+		_, _ = fmt.Fprint(writer, `{"id": 1, "title": "title", "state": "UNRELEASED"}`)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+	expected := &kypo.Error{
+		ResourceName: "training definition",
+		Identifier:   int64(1),
+		Err:          fmt.Errorf("status: 409, body: "),
+	}
+
+	actual := c.DeleteTrainingDefinition(context.Background(), 1)
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestSetTrainingDefinitionStateSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+		assert.Equal(t, "/kypo-rest-training/api/v1/training-definitions/1/states/RELEASED", request.URL.Path)
+		assert.Equal(t, http.MethodPut, request.Method)
+
+		writer.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	err := c.SetTrainingDefinitionState(context.Background(), 1, kypo.TrainingDefinitionStateReleased)
+
+	assert.NoError(t, err)
+}
+
+func TestSetTrainingDefinitionStateNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+	expected := &kypo.Error{
+		ResourceName: "training definition",
+		Identifier:   int64(1),
+		Err:          kypo.ErrNotFound,
+	}
+
+	actual := c.SetTrainingDefinitionState(context.Background(), 1, kypo.TrainingDefinitionStateArchived)
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestGetTrainingDefinitionStateSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-rest-training/api/v1/training-definitions/1", request.URL.Path)
+		assert.Equal(t, http.MethodGet, request.Method)
+
+		_, _ = fmt.Fprint(writer, `{"id": 1, "title": "title", "state": "ARCHIVED"}`)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.GetTrainingDefinitionState(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, kypo.TrainingDefinitionStateArchived, actual)
+}
+
+var trainingDefinitionsPageResponse = `{"content": [
+	{"id": 1, "title": "title", "state": "RELEASED", "last_edited": "2023-11-26T09:03:35.313174494Z",
+		"authors": [{"user_ref_id": 1, "sub": "kypo-admin", "full_name": "Admin", "given_name": "A", "family_name": "Dmin", "iss": "https://oidc"}]},
+	{"id": 2, "title": "title", "state": "RELEASED", "last_edited": "2023-11-27T10:00:00Z", "authors": []}],
+	"pagination": {"number": 1, "number_of_elements": 2, "size": 2, "total_elements": 4, "total_pages": 2}}`
+
+func TestListTrainingDefinitionsSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+		assert.Equal(t, "/kypo-rest-training/api/v1/training-definitions", request.URL.Path)
+		assert.Equal(t, http.MethodGet, request.Method)
+		assert.Equal(t, "1", request.URL.Query().Get("page"))
+		assert.Equal(t, "2", request.URL.Query().Get("size"))
+		assert.Equal(t, "title", request.URL.Query().Get("title"))
+		assert.Equal(t, "RELEASED", request.URL.Query().Get("state"))
+
+		_, _ = fmt.Fprint(writer, trainingDefinitionsPageResponse)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := kypo.Pagination[[]kypo.TrainingDefinitionSummary]{
+		Page:       2,
+		PageSize:   2,
+		PageCount:  2,
+		Count:      2,
+		TotalCount: 4,
+		Results: []kypo.TrainingDefinitionSummary{
+			{
+				Id:         1,
+				Title:      "title",
+				State:      kypo.TrainingDefinitionStateReleased,
+				LastEdited: time.Date(2023, 11, 26, 9, 3, 35, 313174494, time.UTC),
+				Authors: []kypo.User{
+					{Id: 1, Sub: "kypo-admin", FullName: "Admin", GivenName: "A", FamilyName: "Dmin"},
+				},
+			},
+			{
+				Id:         2,
+				Title:      "title",
+				State:      kypo.TrainingDefinitionStateReleased,
+				LastEdited: time.Date(2023, 11, 27, 10, 0, 0, 0, time.UTC),
+				Authors:    []kypo.User{},
+			},
+		},
+	}
+
+	actual, err := c.ListTrainingDefinitions(context.Background(), kypo.ListTrainingDefinitionsOptions{
+		Page:     2,
+		PageSize: 2,
+		Title:    "title",
+		State:    kypo.TrainingDefinitionStateReleased,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expected, actual)
+}
+
+func TestListTrainingDefinitionsByAuthor(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-rest-training/api/v1/training-definitions", request.URL.Path)
+		assert.Equal(t, "100", request.URL.Query().Get("size"))
+		assert.Equal(t, "title", request.URL.Query().Get("title"))
+		assert.Equal(t, "RELEASED", request.URL.Query().Get("state"))
+
+		if request.URL.Query().Get("page") == "0" {
+			_, _ = fmt.Fprint(writer, `{"content": [{"id": 3, "title": "title", "authors": [{"user_ref_id": 1, "sub": "kypo-admin", "mail": "admin@kypo"}]}],
+				"pagination": {"number": 0, "number_of_elements": 1, "size": 100, "total_elements": 3, "total_pages": 2}}`)
+			return
+		}
+		_, _ = fmt.Fprint(writer, `{"content": [
+			{"id": 1, "title": "title", "authors": [{"user_ref_id": 1, "sub": "kypo-admin", "mail": "admin@kypo"}]},
+			{"id": 2, "title": "title", "authors": []}],
+			"pagination": {"number": 1, "number_of_elements": 2, "size": 100, "total_elements": 3, "total_pages": 2}}`)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := kypo.Pagination[[]kypo.TrainingDefinitionSummary]{
+		Page:       2,
+		PageSize:   1,
+		PageCount:  2,
+		Count:      1,
+		TotalCount: 2,
+		Results: []kypo.TrainingDefinitionSummary{
+			{Id: 1, Title: "title", Authors: []kypo.User{{Id: 1, Sub: "kypo-admin", Mail: "admin@kypo"}}},
+		},
+	}
+
+	actual, err := c.ListTrainingDefinitions(context.Background(), kypo.ListTrainingDefinitionsOptions{
+		Page:     2,
+		PageSize: 1,
+		Title:    "title",
+		State:    kypo.TrainingDefinitionStateReleased,
+		Author:   "admin@kypo",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expected, actual)
+}
+
+func TestListTrainingDefinitionsServerError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+	expected := &kypo.Error{
+		ResourceName: "training definitions",
+		Identifier:   "",
+		Err:          fmt.Errorf("status: 500, body: "),
+	}
+
+	actual, err := c.ListTrainingDefinitions(context.Background(), kypo.ListTrainingDefinitionsOptions{})
+
+	assert.Nil(t, actual)
+	assert.Equal(t, expected, err)
+}
@@ -0,0 +1,330 @@
+package kypo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// definitionItem is a level, phase or task of a training definition as returned by the training services' edit endpoints.
+type definitionItem struct {
+	Id        int64  `json:"id"`
+	LevelType string `json:"level_type"`
+	PhaseType string `json:"phase_type"`
+	Order     int64  `json:"order"`
+}
+
+// itemOperations are the requests used by convergeItems to edit a list of levels, phases or tasks.
+type itemOperations struct {
+	create func(itemType string) (int64, error)
+	delete func(id int64) error
+	move   func(id int64, position int) error
+	update func(id int64, index int) error
+}
+
+// convergeItems edits the `current` items so that their types and order match `targetTypes`.
+// Existing items are reused in their original order when their type matches, missing items are created,
+// the remaining items are deleted. All items are then moved to their target position and updated.
+func convergeItems(current []definitionItem, targetTypes []string, itemType func(definitionItem) string, operations itemOperations) error {
+	sort.SliceStable(current, func(i, j int) bool {
+		return current[i].Order < current[j].Order
+	})
+
+	used := make([]bool, len(current))
+	ids := make([]int64, len(targetTypes))
+	var created []int64
+	for i, targetType := range targetTypes {
+		found := false
+		for j, item := range current {
+			if !used[j] && itemType(item) == targetType {
+				used[j] = true
+				ids[i] = item.Id
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+
+		id, err := operations.create(targetType)
+		if err != nil {
+			return err
+		}
+		ids[i] = id
+		created = append(created, id)
+	}
+
+	var order []int64
+	for j, item := range current {
+		if used[j] {
+			order = append(order, item.Id)
+			continue
+		}
+		err := operations.delete(item.Id)
+		if err != nil {
+			return err
+		}
+	}
+	// New items are appended to the end by KYPO
+	order = append(order, created...)
+
+	for i, id := range ids {
+		if order[i] == id {
+			continue
+		}
+		err := operations.move(id, i)
+		if err != nil {
+			return err
+		}
+		order = moveId(order, id, i)
+	}
+
+	for i, id := range ids {
+		err := operations.update(id, i)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func moveId(order []int64, id int64, position int) []int64 {
+	moved := make([]int64, 0, len(order))
+	for _, other := range order {
+		if other != id {
+			moved = append(moved, other)
+		}
+	}
+	moved = append(moved[:position], append([]int64{id}, moved[position:]...)...)
+	return moved
+}
+
+// withId marshals `v` to a JSON object, sets its `id` field and removes the `omitted` fields.
+func withId(v any, id int64, omitted ...string) (map[string]json.RawMessage, error) {
+	encoded, err := marshalJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]json.RawMessage
+	err = json.Unmarshal(encoded, &object)
+	if err != nil {
+		return nil, err
+	}
+	object["id"] = json.RawMessage(fmt.Sprint(id))
+	for _, field := range omitted {
+		delete(object, field)
+	}
+	return object, nil
+}
+
+var levelUpdatePaths = map[LevelType]string{
+	LevelTypeInfo:       "info-levels",
+	LevelTypeTraining:   "training-levels",
+	LevelTypeAssessment: "assessment-levels",
+	LevelTypeAccess:     "access-levels",
+}
+
+// UpdateTrainingDefinition edits the definition given by definitionID in place, so that it matches the `content`
+// of an exported training definition. Unlike deleting and recreating the definition, the id is kept.
+// The metadata is updated and the levels are created, deleted, reordered and updated as needed.
+// Existing levels are reused when their type matches. Requests creating levels are not retried,
+// so a lost response does not create a duplicate level.
+func (c *Client) UpdateTrainingDefinition(ctx context.Context, definitionID int64, content string) (*TrainingDefinition, error) {
+	spec, err := ParseLinearTrainingDefinition(content)
+	if err != nil {
+		return nil, err
+	}
+
+	targetTypes := make([]string, 0, len(spec.Levels))
+	for i, level := range spec.Levels {
+		levelType := level.Base().LevelType
+		if _, ok := levelUpdatePaths[levelType]; !ok {
+			return nil, fmt.Errorf("levels[%d]: unknown level type %q", i, levelType)
+		}
+		targetTypes = append(targetTypes, string(levelType))
+	}
+
+	definitionURL := fmt.Sprintf("%s/kypo-rest-training/api/v1/training-definitions", c.Endpoint)
+
+	metadata, err := withId(spec, definitionID, "levels")
+	if err != nil {
+		return nil, err
+	}
+	err = c.doJSONRequest(ctx, http.MethodPut, definitionURL, metadata, nil, http.StatusNoContent, "training definition", definitionID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := struct {
+		Levels []definitionItem `json:"levels"`
+	}{}
+	err = c.doJSONRequest(ctx, http.MethodGet, fmt.Sprintf("%s/%d", definitionURL, definitionID), nil, &current, http.StatusOK, "training definition", definitionID)
+	if err != nil {
+		return nil, err
+	}
+
+	levelsURL := fmt.Sprintf("%s/%d/levels", definitionURL, definitionID)
+	identifier := fmt.Sprintf("training definition %d", definitionID)
+	err = convergeItems(current.Levels, targetTypes, func(item definitionItem) string { return item.LevelType }, itemOperations{
+		create: func(levelType string) (int64, error) {
+			level := definitionItem{}
+			err := c.doJSONRequestOnce(ctx, http.MethodPost, fmt.Sprintf("%s/%s", levelsURL, levelType), nil, &level, http.StatusOK, "training definition level", identifier)
+			return level.Id, err
+		},
+		delete: func(id int64) error {
+			return c.doJSONRequest(ctx, http.MethodDelete, fmt.Sprintf("%s/%d", levelsURL, id), nil, nil, http.StatusOK, "training definition level", id)
+		},
+		move: func(id int64, position int) error {
+			return c.doJSONRequest(ctx, http.MethodPut, fmt.Sprintf("%s/%d/move-to/%d", levelsURL, id, position), nil, nil, http.StatusNoContent, "training definition level", id)
+		},
+		update: func(id int64, index int) error {
+			level := spec.Levels[index]
+			body, err := withId(level, id)
+			if err != nil {
+				return err
+			}
+			url := fmt.Sprintf("%s/%d/%s", definitionURL, definitionID, levelUpdatePaths[level.Base().LevelType])
+			return c.doJSONRequest(ctx, http.MethodPut, url, body, nil, http.StatusNoContent, "training definition level", id)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	definition := TrainingDefinition{
+		Id:      definitionID,
+		Content: content,
+	}
+
+	return &definition, nil
+}
+
+var phaseUpdatePaths = map[PhaseType]string{
+	PhaseTypeInfo:          "info",
+	PhaseTypeQuestionnaire: "questionnaire",
+	PhaseTypeTraining:      "training",
+	PhaseTypeAccess:        "access",
+}
+
+// UpdateTrainingDefinitionAdaptive edits the adaptive definition given by definitionID in place, so that it matches
+// the `content` of an exported adaptive training definition. Unlike deleting and recreating the definition, the id is kept.
+// The metadata is updated and the phases, and tasks of training phases, are created, deleted, reordered
+// and updated as needed. Existing phases are reused when their type matches. Requests creating phases and tasks
+// are not retried, like in UpdateTrainingDefinition.
+func (c *Client) UpdateTrainingDefinitionAdaptive(ctx context.Context, definitionID int64, content string) (*TrainingDefinitionAdaptive, error) {
+	spec, err := ParseAdaptiveTrainingDefinition(content)
+	if err != nil {
+		return nil, err
+	}
+
+	targetTypes := make([]string, 0, len(spec.Phases))
+	for i, phase := range spec.Phases {
+		phaseType := phase.Base().PhaseType
+		if _, ok := phaseUpdatePaths[phaseType]; !ok {
+			return nil, fmt.Errorf("phases[%d]: unknown phase type %q", i, phaseType)
+		}
+		targetTypes = append(targetTypes, string(phaseType))
+	}
+
+	definitionURL := fmt.Sprintf("%s/kypo-adaptive-training/api/v1/training-definitions", c.Endpoint)
+
+	metadata, err := withId(spec, definitionID, "phases")
+	if err != nil {
+		return nil, err
+	}
+	err = c.doJSONRequest(ctx, http.MethodPut, definitionURL, metadata, nil, http.StatusNoContent, "training definition adaptive", definitionID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := struct {
+		Phases []definitionItem `json:"phases"`
+	}{}
+	err = c.doJSONRequest(ctx, http.MethodGet, fmt.Sprintf("%s/%d", definitionURL, definitionID), nil, &current, http.StatusOK, "training definition adaptive", definitionID)
+	if err != nil {
+		return nil, err
+	}
+
+	phasesURL := fmt.Sprintf("%s/%d/phases", definitionURL, definitionID)
+	identifier := fmt.Sprintf("training definition adaptive %d", definitionID)
+	err = convergeItems(current.Phases, targetTypes, func(item definitionItem) string { return item.PhaseType }, itemOperations{
+		create: func(phaseType string) (int64, error) {
+			phase := definitionItem{}
+			request := struct {
+				PhaseType string `json:"phase_type"`
+			}{phaseType}
+			err := c.doJSONRequestOnce(ctx, http.MethodPost, phasesURL, request, &phase, http.StatusCreated, "training definition adaptive phase", identifier)
+			return phase.Id, err
+		},
+		delete: func(id int64) error {
+			return c.doJSONRequest(ctx, http.MethodDelete, fmt.Sprintf("%s/%d", phasesURL, id), nil, nil, http.StatusOK, "training definition adaptive phase", id)
+		},
+		move: func(id int64, position int) error {
+			return c.doJSONRequest(ctx, http.MethodPut, fmt.Sprintf("%s/%d/move-to/%d", phasesURL, id, position), nil, nil, http.StatusOK, "training definition adaptive phase", id)
+		},
+		update: func(id int64, index int) error {
+			phase := spec.Phases[index]
+			if training, ok := phase.(*TrainingPhase); ok {
+				err := c.updateTrainingPhaseTasks(ctx, fmt.Sprintf("%s/%d", phasesURL, id), id, training.Tasks)
+				if err != nil {
+					return err
+				}
+			}
+
+			body, err := withId(phase, id, "tasks")
+			if err != nil {
+				return err
+			}
+			url := fmt.Sprintf("%s/%d/%s", phasesURL, id, phaseUpdatePaths[phase.Base().PhaseType])
+			return c.doJSONRequest(ctx, http.MethodPut, url, body, nil, http.StatusOK, "training definition adaptive phase", id)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	definition := TrainingDefinitionAdaptive{
+		Id:      definitionID,
+		Content: content,
+	}
+
+	return &definition, nil
+}
+
+// updateTrainingPhaseTasks converges the tasks of the training phase at `phaseURL` to the given `tasks`.
+func (c *Client) updateTrainingPhaseTasks(ctx context.Context, phaseURL string, phaseID int64, tasks []Task) error {
+	current := struct {
+		Tasks []definitionItem `json:"tasks"`
+	}{}
+	err := c.doJSONRequest(ctx, http.MethodGet, phaseURL, nil, &current, http.StatusOK, "training definition adaptive phase", phaseID)
+	if err != nil {
+		return err
+	}
+
+	targetTypes := make([]string, len(tasks))
+	tasksURL := phaseURL + "/tasks"
+	identifier := fmt.Sprintf("training definition adaptive phase %d", phaseID)
+	return convergeItems(current.Tasks, targetTypes, func(definitionItem) string { return "" }, itemOperations{
+		create: func(string) (int64, error) {
+			task := definitionItem{}
+			err := c.doJSONRequestOnce(ctx, http.MethodPost, tasksURL, nil, &task, http.StatusCreated, "training phase task", identifier)
+			return task.Id, err
+		},
+		delete: func(id int64) error {
+			return c.doJSONRequest(ctx, http.MethodDelete, fmt.Sprintf("%s/%d", tasksURL, id), nil, nil, http.StatusOK, "training phase task", id)
+		},
+		move: func(id int64, position int) error {
+			return c.doJSONRequest(ctx, http.MethodPut, fmt.Sprintf("%s/%d/move-to/%d", tasksURL, id, position), nil, nil, http.StatusOK, "training phase task", id)
+		},
+		update: func(id int64, index int) error {
+			body, err := withId(tasks[index], id)
+			if err != nil {
+				return err
+			}
+			return c.doJSONRequest(ctx, http.MethodPut, fmt.Sprintf("%s/%d", tasksURL, id), body, nil, http.StatusOK, "training phase task", id)
+		},
+	})
+}
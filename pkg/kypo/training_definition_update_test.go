@@ -0,0 +1,245 @@
+package kypo_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type editResponse struct {
+	status int
+	body   string
+}
+
+// editServer responds to the requests given by `responses` keyed by method and path
+// and records the requests and their JSON object bodies. Repeated requests get the following responses, the last one is kept.
+func editServer(t *testing.T, responses map[string][]editResponse, requests *[]string, bodies map[string][]map[string]any) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+		key := request.Method + " " + request.URL.Path
+		*requests = append(*requests, key)
+
+		body, _ := io.ReadAll(request.Body)
+		if len(body) > 0 && body[0] == '{' {
+			var object map[string]any
+			assert.NoError(t, json.Unmarshal(body, &object))
+			bodies[key] = append(bodies[key], object)
+		}
+
+		keyResponses := responses[key]
+		if len(keyResponses) == 0 {
+			t.Errorf("unexpected request %s", key)
+			writer.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		response := keyResponses[0]
+		if len(keyResponses) > 1 {
+			responses[key] = keyResponses[1:]
+		}
+		writer.WriteHeader(response.status)
+		_, _ = fmt.Fprint(writer, response.body)
+	}))
+}
+
+func TestUpdateTrainingDefinition(t *testing.T) {
+	content := readTestdata(t, "training_definition.json")
+	prefix := "/kypo-rest-training/api/v1/training-definitions"
+	responses := map[string][]editResponse{
+		"PUT " + prefix: {{http.StatusNoContent, ""}},
+		"GET " + prefix + "/1": {{http.StatusOK, `{"id": 1, "levels": [
+			{"id": 3, "level_type": "ASSESSMENT_LEVEL", "order": 2},
+			{"id": 1, "level_type": "INFO_LEVEL", "order": 0},
+			{"id": 4, "level_type": "INFO_LEVEL", "order": 3},
+			{"id": 2, "level_type": "TRAINING_LEVEL", "order": 1}]}`}},
+		"POST " + prefix + "/1/levels/ACCESS_LEVEL":   {{http.StatusOK, `{"id": 10, "level_type": "ACCESS_LEVEL", "order": 4}`}},
+		"POST " + prefix + "/1/levels/TRAINING_LEVEL": {{http.StatusOK, `{"id": 11, "level_type": "TRAINING_LEVEL", "order": 5}`}},
+		"DELETE " + prefix + "/1/levels/4":            {{http.StatusOK, `[]`}},
+		"PUT " + prefix + "/1/levels/10/move-to/1":    {{http.StatusNoContent, ""}},
+		"PUT " + prefix + "/1/levels/11/move-to/3":    {{http.StatusNoContent, ""}},
+		"PUT " + prefix + "/1/info-levels":            {{http.StatusNoContent, ""}},
+		"PUT " + prefix + "/1/access-levels":          {{http.StatusNoContent, ""}},
+		"PUT " + prefix + "/1/training-levels":        {{http.StatusNoContent, ""}},
+		"PUT " + prefix + "/1/assessment-levels":      {{http.StatusNoContent, ""}},
+	}
+	var requests []string
+	bodies := map[string][]map[string]any{}
+	ts := editServer(t, responses, &requests, bodies)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := kypo.TrainingDefinition{
+		Id:      1,
+		Content: content,
+	}
+
+	actual, err := c.UpdateTrainingDefinition(context.Background(), 1, content)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expected, actual)
+	assert.Equal(t, []string{
+		"PUT " + prefix,
+		"GET " + prefix + "/1",
+		"POST " + prefix + "/1/levels/ACCESS_LEVEL",
+		"POST " + prefix + "/1/levels/TRAINING_LEVEL",
+		"DELETE " + prefix + "/1/levels/4",
+		"PUT " + prefix + "/1/levels/10/move-to/1",
+		"PUT " + prefix + "/1/levels/11/move-to/3",
+		"PUT " + prefix + "/1/info-levels",
+		"PUT " + prefix + "/1/access-levels",
+		"PUT " + prefix + "/1/training-levels",
+		"PUT " + prefix + "/1/training-levels",
+		"PUT " + prefix + "/1/assessment-levels",
+	}, requests)
+
+	metadata := bodies["PUT "+prefix][0]
+	assert.Equal(t, float64(1), metadata["id"])
+	assert.Equal(t, "Junior hacker", metadata["title"])
+	assert.NotContains(t, metadata, "levels")
+
+	trainingLevels := bodies["PUT "+prefix+"/1/training-levels"]
+	require.Len(t, trainingLevels, 2)
+	assert.Equal(t, float64(2), trainingLevels[0]["id"])
+	assert.Equal(t, "Scan the network", trainingLevels[0]["title"])
+	assert.Equal(t, float64(11), trainingLevels[1]["id"])
+	assert.Equal(t, "Crack the password", trainingLevels[1]["title"])
+}
+
+func TestUpdateTrainingDefinitionUnknownLevel(t *testing.T) {
+	content := `{"title": "title", "levels": [{"title": "level", "level_type": "CUSTOM_LEVEL", "order": 0}]}`
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		t.Errorf("unexpected request %s %s", request.Method, request.URL.Path)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.UpdateTrainingDefinition(context.Background(), 1, content)
+
+	assert.Nil(t, actual)
+	assert.EqualError(t, err, `levels[0]: unknown level type "CUSTOM_LEVEL"`)
+}
+
+func TestUpdateTrainingDefinitionNotFound(t *testing.T) {
+	var requests []string
+	ts := editServer(t, map[string][]editResponse{
+		"PUT /kypo-rest-training/api/v1/training-definitions": {{http.StatusNotFound, ""}},
+	}, &requests, map[string][]map[string]any{})
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.Error{
+		ResourceName: "training definition",
+		Identifier:   int64(1),
+		Err:          kypo.ErrNotFound,
+	}
+
+	actual, err := c.UpdateTrainingDefinition(context.Background(), 1, trainingDefinitionJsonString)
+
+	assert.Nil(t, actual)
+	assert.Equal(t, expected, err)
+}
+
+func TestUpdateTrainingDefinitionCreateNotRetried(t *testing.T) {
+	prefix := "/kypo-rest-training/api/v1/training-definitions"
+	var requests []string
+	ts := editServer(t, map[string][]editResponse{
+		"PUT " + prefix:                           {{http.StatusNoContent, ""}},
+		"GET " + prefix + "/1":                    {{http.StatusOK, `{"id": 1, "levels": []}`}},
+		"POST " + prefix + "/1/levels/INFO_LEVEL": {{http.StatusInternalServerError, ""}},
+	}, &requests, map[string][]map[string]any{})
+	defer ts.Close()
+
+	c := minimalClient(ts)
+	c.RetryCount = 2
+
+	_, err := c.UpdateTrainingDefinition(context.Background(), 1,
+		`{"title": "title", "levels": [{"title": "level", "level_type": "INFO_LEVEL", "order": 0}]}`)
+
+	assert.Error(t, err)
+	assert.Equal(t, []string{"PUT " + prefix, "GET " + prefix + "/1", "POST " + prefix + "/1/levels/INFO_LEVEL"}, requests)
+}
+
+func TestUpdateTrainingDefinitionAdaptive(t *testing.T) {
+	content := readTestdata(t, "training_definition_adaptive.json")
+	prefix := "/kypo-adaptive-training/api/v1/training-definitions"
+	phases := prefix + "/1/phases"
+	responses := map[string][]editResponse{
+		"PUT " + prefix: {{http.StatusNoContent, ""}},
+		"GET " + prefix + "/1": {{http.StatusOK, `{"id": 1, "phases": [
+			{"id": 1, "phase_type": "INFO", "order": 0},
+			{"id": 2, "phase_type": "TRAINING", "order": 1},
+			{"id": 3, "phase_type": "QUESTIONNAIRE", "order": 2}]}`}},
+		"POST " + phases: {
+			{http.StatusCreated, `{"id": 10, "phase_type": "ACCESS", "order": 3}`},
+			{http.StatusCreated, `{"id": 11, "phase_type": "TRAINING", "order": 4}`},
+		},
+		"PUT " + phases + "/10/move-to/1":    {{http.StatusOK, ""}},
+		"PUT " + phases + "/3/move-to/2":     {{http.StatusOK, ""}},
+		"PUT " + phases + "/1/info":          {{http.StatusOK, ""}},
+		"PUT " + phases + "/10/access":       {{http.StatusOK, ""}},
+		"PUT " + phases + "/3/questionnaire": {{http.StatusOK, ""}},
+		"GET " + phases + "/2":               {{http.StatusOK, `{"id": 2, "tasks": [{"id": 20, "order": 0}]}`}},
+		"POST " + phases + "/2/tasks":        {{http.StatusCreated, `{"id": 30, "order": 1}`}},
+		"PUT " + phases + "/2/tasks/20":      {{http.StatusOK, ""}},
+		"PUT " + phases + "/2/tasks/30":      {{http.StatusOK, ""}},
+		"PUT " + phases + "/2/training":      {{http.StatusOK, ""}},
+		"GET " + phases + "/11":              {{http.StatusOK, `{"id": 11, "tasks": [{"id": 22, "order": 1}, {"id": 21, "order": 0}]}`}},
+		"DELETE " + phases + "/11/tasks/22":  {{http.StatusOK, ""}},
+		"PUT " + phases + "/11/tasks/21":     {{http.StatusOK, ""}},
+		"PUT " + phases + "/11/training":     {{http.StatusOK, ""}},
+	}
+	var requests []string
+	bodies := map[string][]map[string]any{}
+	ts := editServer(t, responses, &requests, bodies)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := kypo.TrainingDefinitionAdaptive{
+		Id:      1,
+		Content: content,
+	}
+
+	actual, err := c.UpdateTrainingDefinitionAdaptive(context.Background(), 1, content)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expected, actual)
+	assert.Equal(t, []string{
+		"PUT " + prefix,
+		"GET " + prefix + "/1",
+		"POST " + phases,
+		"POST " + phases,
+		"PUT " + phases + "/10/move-to/1",
+		"PUT " + phases + "/3/move-to/2",
+		"PUT " + phases + "/1/info",
+		"PUT " + phases + "/10/access",
+		"PUT " + phases + "/3/questionnaire",
+		"GET " + phases + "/2",
+		"POST " + phases + "/2/tasks",
+		"PUT " + phases + "/2/tasks/20",
+		"PUT " + phases + "/2/tasks/30",
+		"PUT " + phases + "/2/training",
+		"GET " + phases + "/11",
+		"DELETE " + phases + "/11/tasks/22",
+		"PUT " + phases + "/11/tasks/21",
+		"PUT " + phases + "/11/training",
+	}, requests)
+
+	assert.Equal(t, []map[string]any{{"phase_type": "ACCESS"}, {"phase_type": "TRAINING"}}, bodies["POST "+phases])
+	assert.NotContains(t, bodies["PUT "+prefix][0], "phases")
+
+	training := bodies["PUT "+phases+"/2/training"][0]
+	assert.Equal(t, float64(2), training["id"])
+	assert.NotContains(t, training, "tasks")
+	assert.Contains(t, training, "decision_matrix")
+	assert.Equal(t, float64(30), bodies["PUT "+phases+"/2/tasks/30"][0]["id"])
+}
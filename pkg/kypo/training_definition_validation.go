@@ -0,0 +1,401 @@
+package kypo
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ValidationError is a problem found in the content of a training definition.
+// The Path addresses the invalid field in the content, for example `levels[2].hints[0].hint_penalty`.
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ValidationErrors is a list of problems found in the content of a training definition.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, validationError := range e {
+		messages = append(messages, validationError.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *ValidationErrors) add(path, format string, args ...any) {
+	*e = append(*e, ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+var answerVariableNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateAnswer checks that an answer written as a regular expression between slashes, for example `/^flag\{.*\}$/`,
+// compiles. Other answers are matched literally and are not checked.
+func validateAnswer(errs *ValidationErrors, path, answer string) {
+	if !isRegexAnswer(answer) {
+		return
+	}
+	_, err := regexp.Compile(answer[1 : len(answer)-1])
+	if err != nil {
+		errs.add(path, "invalid regular expression: %s", err)
+	}
+}
+
+// isRegexAnswer reports whether the answer is written as a regular expression between slashes.
+func isRegexAnswer(answer string) bool {
+	return len(answer) >= 2 && strings.HasPrefix(answer, "/") && strings.HasSuffix(answer, "/")
+}
+
+// ValidateTrainingDefinition checks the content of a linear training definition without a KYPO instance.
+// Answers written between slashes, such as `/^flag\{.*\}$/`, are checked to be valid regular expressions.
+// It returns nil if no problems were found.
+func ValidateTrainingDefinition(content string) ValidationErrors {
+	var errs ValidationErrors
+	spec, err := ParseLinearTrainingDefinition(content)
+	if err != nil {
+		errs.add("", "invalid JSON: %s", err)
+		return errs
+	}
+
+	validateDefinitionMetadata(&errs, spec.Title, spec.State)
+	for i, level := range spec.Levels {
+		path := fmt.Sprintf("levels[%d]", i)
+		base := level.Base()
+		if base.Order != int64(i) {
+			errs.add(path+".order", "must be %d, got %d", i, base.Order)
+		}
+		if base.Title == "" {
+			errs.add(path+".title", "must not be empty")
+		}
+		if base.MaxScore < 0 {
+			errs.add(path+".max_score", "must not be negative")
+		}
+		if base.EstimatedDuration < 0 {
+			errs.add(path+".estimated_duration", "must not be negative")
+		}
+
+		switch level := level.(type) {
+		case *InfoLevel:
+			if level.Content == "" {
+				errs.add(path+".content", "must not be empty")
+			}
+		case *AccessLevel:
+			if level.Passkey == "" {
+				errs.add(path+".passkey", "must not be empty")
+			}
+		case *TrainingLevel:
+			validateTrainingLevel(&errs, path, level)
+		case *AssessmentLevel:
+			validateAssessmentLevel(&errs, path, level)
+		default:
+			errs.add(path+".level_type", "unknown level type %q", base.LevelType)
+		}
+	}
+
+	return errs
+}
+
+// ValidateTrainingDefinitionAdaptive checks the content of an adaptive training definition without a KYPO instance.
+// Answers are checked like in ValidateTrainingDefinition. It returns nil if no problems were found.
+func ValidateTrainingDefinitionAdaptive(content string) ValidationErrors {
+	var errs ValidationErrors
+	spec, err := ParseAdaptiveTrainingDefinition(content)
+	if err != nil {
+		errs.add("", "invalid JSON: %s", err)
+		return errs
+	}
+
+	validateDefinitionMetadata(&errs, spec.Title, spec.State)
+	trainingPhases := 0
+	for i, phase := range spec.Phases {
+		path := fmt.Sprintf("phases[%d]", i)
+		base := phase.Base()
+		if base.Order != int64(i) {
+			errs.add(path+".order", "must be %d, got %d", i, base.Order)
+		}
+		if base.Title == "" {
+			errs.add(path+".title", "must not be empty")
+		}
+
+		switch phase := phase.(type) {
+		case *InfoPhase:
+			if phase.Content == "" {
+				errs.add(path+".content", "must not be empty")
+			}
+		case *AccessPhase:
+			if phase.Passkey == "" {
+				errs.add(path+".passkey", "must not be empty")
+			}
+		case *QuestionnairePhase:
+			validateQuestionnairePhase(&errs, path, phase, spec.Phases)
+		case *TrainingPhase:
+			validateTrainingPhase(&errs, path, phase, trainingPhases)
+			trainingPhases++
+		default:
+			errs.add(path+".phase_type", "unknown phase type %q", base.PhaseType)
+		}
+	}
+
+	return errs
+}
+
+func validateDefinitionMetadata(errs *ValidationErrors, title string, state TrainingDefinitionState) {
+	if title == "" {
+		errs.add("title", "must not be empty")
+	}
+	switch state {
+	case TrainingDefinitionStateUnreleased, TrainingDefinitionStateReleased, TrainingDefinitionStateArchived:
+	default:
+		errs.add("state", "unknown state %q", state)
+	}
+}
+
+func validateTrainingLevel(errs *ValidationErrors, path string, level *TrainingLevel) {
+	if level.Content == "" {
+		errs.add(path+".content", "must not be empty")
+	}
+	if level.VariantAnswers && level.AnswerVariableName == "" {
+		errs.add(path+".answer_variable_name", "must be set when variant answers are used")
+	}
+	if level.AnswerVariableName == "" && level.Answer == "" {
+		errs.add(path+".answer", "must not be empty")
+	}
+	validateAnswer(errs, path+".answer", level.Answer)
+	if level.AnswerVariableName != "" && !answerVariableNameRegex.MatchString(level.AnswerVariableName) {
+		errs.add(path+".answer_variable_name", "must match %s", answerVariableNameRegex)
+	}
+	if level.IncorrectAnswerLimit < 1 {
+		errs.add(path+".incorrect_answer_limit", "must be at least 1")
+	}
+
+	var penalties int64
+	for i, hint := range level.Hints {
+		hintPath := fmt.Sprintf("%s.hints[%d]", path, i)
+		if hint.Order != int64(i) {
+			errs.add(hintPath+".order", "must be %d, got %d", i, hint.Order)
+		}
+		if hint.Title == "" {
+			errs.add(hintPath+".title", "must not be empty")
+		}
+		if hint.Content == "" {
+			errs.add(hintPath+".content", "must not be empty")
+		}
+		if hint.HintPenalty < 0 {
+			errs.add(hintPath+".hint_penalty", "must not be negative")
+		}
+		if hint.HintPenalty > level.MaxScore {
+			errs.add(hintPath+".hint_penalty", "%d exceeds the max score %d of the level", hint.HintPenalty, level.MaxScore)
+		}
+		penalties += hint.HintPenalty
+	}
+	if penalties > level.MaxScore {
+		errs.add(path+".hints", "total hint penalty %d exceeds the max score %d of the level", penalties, level.MaxScore)
+	}
+}
+
+func validateAssessmentLevel(errs *ValidationErrors, path string, level *AssessmentLevel) {
+	test := level.AssessmentType == "TEST"
+	if !test && level.AssessmentType != "QUESTIONNAIRE" {
+		errs.add(path+".assessment_type", "unknown assessment type %q", level.AssessmentType)
+	}
+
+	var points int64
+	for i, question := range level.Questions {
+		questionPath := fmt.Sprintf("%s.questions[%d]", path, i)
+		if question.Order != int64(i) {
+			errs.add(questionPath+".order", "must be %d, got %d", i, question.Order)
+		}
+		if question.Text == "" {
+			errs.add(questionPath+".text", "must not be empty")
+		}
+		if question.Points < 0 {
+			errs.add(questionPath+".points", "must not be negative")
+		}
+		if question.Penalty < 0 {
+			errs.add(questionPath+".penalty", "must not be negative")
+		}
+		points += question.Points
+
+		correct := 0
+		for j, choice := range question.Choices {
+			if choice.Order != int64(j) {
+				errs.add(fmt.Sprintf("%s.choices[%d].order", questionPath, j), "must be %d, got %d", j, choice.Order)
+			}
+			if choice.Text == "" {
+				errs.add(fmt.Sprintf("%s.choices[%d].text", questionPath, j), "must not be empty")
+			}
+			if choice.Correct {
+				correct++
+				if question.QuestionType == "FFQ" {
+					validateAnswer(errs, fmt.Sprintf("%s.choices[%d].text", questionPath, j), choice.Text)
+				}
+			}
+		}
+
+		switch question.QuestionType {
+		case "MCQ":
+			if len(question.Choices) < 2 {
+				errs.add(questionPath+".choices", "multiple choice question must have at least 2 choices")
+			}
+			if test && correct == 0 {
+				errs.add(questionPath+".choices", "must contain a correct choice in a test")
+			}
+		case "FFQ":
+			if test && correct == 0 {
+				errs.add(questionPath+".choices", "must contain a correct answer in a test")
+			}
+		case "EMI":
+			validateExtendedMatching(errs, questionPath, question)
+		default:
+			errs.add(questionPath+".question_type", "unknown question type %q", question.QuestionType)
+		}
+	}
+
+	if test && points > level.MaxScore {
+		errs.add(path+".questions", "total points %d exceed the max score %d of the level", points, level.MaxScore)
+	}
+}
+
+func validateExtendedMatching(errs *ValidationErrors, path string, question AssessmentQuestion) {
+	if len(question.ExtendedMatchingOptions) == 0 {
+		errs.add(path+".extended_matching_options", "must not be empty")
+	}
+	if len(question.ExtendedMatchingStatements) == 0 {
+		errs.add(path+".extended_matching_statements", "must not be empty")
+	}
+
+	options := map[int64]bool{}
+	for i, option := range question.ExtendedMatchingOptions {
+		if option.Order != int64(i) {
+			errs.add(fmt.Sprintf("%s.extended_matching_options[%d].order", path, i), "must be %d, got %d", i, option.Order)
+		}
+		options[option.Order] = true
+	}
+	for i, statement := range question.ExtendedMatchingStatements {
+		statementPath := fmt.Sprintf("%s.extended_matching_statements[%d]", path, i)
+		if statement.Order != int64(i) {
+			errs.add(statementPath+".order", "must be %d, got %d", i, statement.Order)
+		}
+		if !options[statement.CorrectOptionOrder] {
+			errs.add(statementPath+".correct_option_order", "option %d does not exist", statement.CorrectOptionOrder)
+		}
+	}
+}
+
+func validateQuestionnairePhase(errs *ValidationErrors, path string, phase *QuestionnairePhase, phases Phases) {
+	adaptive := phase.QuestionnaireType == "ADAPTIVE"
+	if !adaptive && phase.QuestionnaireType != "GENERAL" {
+		errs.add(path+".questionnaire_type", "unknown questionnaire type %q", phase.QuestionnaireType)
+	}
+
+	for i, question := range phase.Questions {
+		questionPath := fmt.Sprintf("%s.questions[%d]", path, i)
+		if question.Order != int64(i) {
+			errs.add(questionPath+".order", "must be %d, got %d", i, question.Order)
+		}
+		if question.Text == "" {
+			errs.add(questionPath+".text", "must not be empty")
+		}
+
+		correct := 0
+		for j, choice := range question.Choices {
+			if choice.Order != int64(j) {
+				errs.add(fmt.Sprintf("%s.choices[%d].order", questionPath, j), "must be %d, got %d", j, choice.Order)
+			}
+			if choice.Correct {
+				correct++
+				if question.QuestionType == "FFQ" {
+					validateAnswer(errs, fmt.Sprintf("%s.choices[%d].text", questionPath, j), choice.Text)
+				}
+			}
+		}
+
+		switch question.QuestionType {
+		case "MCQ":
+			if len(question.Choices) < 2 {
+				errs.add(questionPath+".choices", "multiple choice question must have at least 2 choices")
+			}
+			if adaptive && correct == 0 {
+				errs.add(questionPath+".choices", "must contain a correct choice in an adaptive questionnaire")
+			}
+		case "FFQ", "RFQ":
+		default:
+			errs.add(questionPath+".question_type", "unknown question type %q", question.QuestionType)
+		}
+	}
+
+	if !adaptive && len(phase.PhaseRelations) != 0 {
+		errs.add(path+".phase_relations", "must be empty in a general questionnaire")
+	}
+	for i, relation := range phase.PhaseRelations {
+		relationPath := fmt.Sprintf("%s.phase_relations[%d]", path, i)
+		if relation.Order != int64(i) {
+			errs.add(relationPath+".order", "must be %d, got %d", i, relation.Order)
+		}
+		if relation.SuccessRate < 0 || relation.SuccessRate > 100 {
+			errs.add(relationPath+".success_rate", "must be between 0 and 100")
+		}
+		if relation.PhaseOrder < 0 || relation.PhaseOrder >= int64(len(phases)) {
+			errs.add(relationPath+".phase_order", "phase %d does not exist", relation.PhaseOrder)
+		} else if _, ok := phases[relation.PhaseOrder].(*TrainingPhase); !ok {
+			errs.add(relationPath+".phase_order", "phase %d is not a training phase", relation.PhaseOrder)
+		}
+		if len(relation.QuestionOrders) == 0 {
+			errs.add(relationPath+".question_orders", "must not be empty")
+		}
+		for j, questionOrder := range relation.QuestionOrders {
+			if questionOrder < 0 || questionOrder >= int64(len(phase.Questions)) {
+				errs.add(fmt.Sprintf("%s.question_orders[%d]", relationPath, j), "question %d does not exist", questionOrder)
+			}
+		}
+	}
+}
+
+func validateTrainingPhase(errs *ValidationErrors, path string, phase *TrainingPhase, precedingTrainingPhases int) {
+	if phase.AllowedWrongAnswers < 0 {
+		errs.add(path+".allowed_wrong_answers", "must not be negative")
+	}
+	if phase.AllowedCommands < 0 {
+		errs.add(path+".allowed_commands", "must not be negative")
+	}
+	if len(phase.Tasks) == 0 {
+		errs.add(path+".tasks", "must not be empty")
+	}
+	for i, task := range phase.Tasks {
+		taskPath := fmt.Sprintf("%s.tasks[%d]", path, i)
+		if task.Order != int64(i) {
+			errs.add(taskPath+".order", "must be %d, got %d", i, task.Order)
+		}
+		if task.Title == "" {
+			errs.add(taskPath+".title", "must not be empty")
+		}
+		if task.Content == "" {
+			errs.add(taskPath+".content", "must not be empty")
+		}
+		if task.Answer == "" {
+			errs.add(taskPath+".answer", "must not be empty")
+		}
+		validateAnswer(errs, taskPath+".answer", task.Answer)
+		if task.IncorrectAnswerLimit < 1 {
+			errs.add(taskPath+".incorrect_answer_limit", "must be at least 1")
+		}
+	}
+
+	if len(phase.DecisionMatrix) != precedingTrainingPhases+1 {
+		errs.add(path+".decision_matrix", "must have %d rows, one for each training phase up to this one, got %d",
+			precedingTrainingPhases+1, len(phase.DecisionMatrix))
+	}
+	for i, row := range phase.DecisionMatrix {
+		if row.Order != int64(i) {
+			errs.add(fmt.Sprintf("%s.decision_matrix[%d].order", path, i), "must be %d, got %d", i, row.Order)
+		}
+	}
+}
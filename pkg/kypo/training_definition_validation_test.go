@@ -0,0 +1,122 @@
+package kypo_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+	"testing"
+)
+
+func TestValidateTrainingDefinitionValid(t *testing.T) {
+	assert.Nil(t, kypo.ValidateTrainingDefinition(readTestdata(t, "training_definition.json")))
+	assert.Nil(t, kypo.ValidateTrainingDefinition(trainingDefinitionJsonString))
+}
+
+func TestValidateTrainingDefinitionInvalidJSON(t *testing.T) {
+	errs := kypo.ValidateTrainingDefinition(`{"title": `)
+
+	require.Len(t, errs, 1)
+	assert.Equal(t, "", errs[0].Path)
+	assert.Contains(t, errs[0].Message, "invalid JSON")
+}
+
+func TestValidateTrainingDefinitionInvalid(t *testing.T) {
+	spec, err := kypo.ParseLinearTrainingDefinition(readTestdata(t, "training_definition.json"))
+	require.NoError(t, err)
+
+	spec.Title = ""
+	spec.Levels[1].Base().Order = 5
+	training := spec.Levels[2].(*kypo.TrainingLevel)
+	training.Answer = ""
+	training.Hints[1].HintPenalty = 18
+	variant := spec.Levels[3].(*kypo.TrainingLevel)
+	variant.AnswerVariableName = "pass word"
+	assessment := spec.Levels[4].(*kypo.AssessmentLevel)
+	assessment.Questions[0].Choices[0].Correct = false
+	assessment.Questions[2].ExtendedMatchingStatements[1].CorrectOptionOrder = 2
+
+	content, err := spec.Content()
+	require.NoError(t, err)
+
+	expected := kypo.ValidationErrors{
+		{Path: "title", Message: "must not be empty"},
+		{Path: "levels[1].order", Message: "must be 1, got 5"},
+		{Path: "levels[2].answer", Message: "must not be empty"},
+		{Path: "levels[2].hints", Message: "total hint penalty 21 exceeds the max score 20 of the level"},
+		{Path: "levels[3].answer_variable_name", Message: "must match ^[A-Za-z_][A-Za-z0-9_]*$"},
+		{Path: "levels[4].questions[0].choices", Message: "must contain a correct choice in a test"},
+		{Path: "levels[4].questions[2].extended_matching_statements[1].correct_option_order", Message: "option 2 does not exist"},
+	}
+
+	actual := kypo.ValidateTrainingDefinition(content)
+
+	assert.Equal(t, expected, actual)
+	assert.EqualError(t, actual, "title: must not be empty; levels[1].order: must be 1, got 5; "+
+		"levels[2].answer: must not be empty; levels[2].hints: total hint penalty 21 exceeds the max score 20 of the level; "+
+		"levels[3].answer_variable_name: must match ^[A-Za-z_][A-Za-z0-9_]*$; "+
+		"levels[4].questions[0].choices: must contain a correct choice in a test; "+
+		"levels[4].questions[2].extended_matching_statements[1].correct_option_order: option 2 does not exist")
+}
+
+func TestValidateTrainingDefinitionRegexAnswers(t *testing.T) {
+	content := `{"title": "title", "state": "UNRELEASED", "levels": [
+		{"title": "valid", "level_type": "TRAINING_LEVEL", "order": 0, "content": "c", "answer": "/^flag\\{[a-z]+\\}$/",
+			"incorrect_answer_limit": 5},
+		{"title": "invalid", "level_type": "TRAINING_LEVEL", "order": 1, "content": "c", "answer": "/flag(/",
+			"incorrect_answer_limit": 5},
+		{"title": "literal", "level_type": "TRAINING_LEVEL", "order": 2, "content": "c", "answer": "flag(",
+			"incorrect_answer_limit": 5},
+		{"title": "quiz", "level_type": "ASSESSMENT_LEVEL", "order": 3, "assessment_type": "QUESTIONNAIRE", "questions": [
+			{"question_type": "FFQ", "text": "port?", "order": 0, "choices": [{"text": "/[0-9+/", "correct": true, "order": 0}]}]}]}`
+
+	expected := kypo.ValidationErrors{
+		{Path: "levels[1].answer", Message: "invalid regular expression: error parsing regexp: missing closing ): `flag(`"},
+		{Path: "levels[3].questions[0].choices[0].text", Message: "invalid regular expression: error parsing regexp: missing closing ]: `[0-9+`"},
+	}
+
+	assert.Equal(t, expected, kypo.ValidateTrainingDefinition(content))
+}
+
+func TestValidateTrainingDefinitionUnknownLevel(t *testing.T) {
+	content := `{"title": "title", "state": "UNRELEASED", "levels": [{"title": "level", "level_type": "CUSTOM_LEVEL", "order": 0}]}`
+
+	expected := kypo.ValidationErrors{
+		{Path: "levels[0].level_type", Message: `unknown level type "CUSTOM_LEVEL"`},
+	}
+
+	assert.Equal(t, expected, kypo.ValidateTrainingDefinition(content))
+}
+
+func TestValidateTrainingDefinitionAdaptiveValid(t *testing.T) {
+	assert.Nil(t, kypo.ValidateTrainingDefinitionAdaptive(readTestdata(t, "training_definition_adaptive.json")))
+	assert.Nil(t, kypo.ValidateTrainingDefinitionAdaptive(trainingDefinitionAdaptiveJsonString))
+}
+
+func TestValidateTrainingDefinitionAdaptiveInvalid(t *testing.T) {
+	spec, err := kypo.ParseAdaptiveTrainingDefinition(readTestdata(t, "training_definition_adaptive.json"))
+	require.NoError(t, err)
+
+	spec.State = "DRAFT"
+	questionnaire := spec.Phases[2].(*kypo.QuestionnairePhase)
+	questionnaire.PhaseRelations[0].PhaseOrder = 1
+	questionnaire.PhaseRelations[1].QuestionOrders = []int64{3}
+	first := spec.Phases[3].(*kypo.TrainingPhase)
+	first.Tasks[0].Answer = "/(/"
+	first.Tasks[1].Answer = ""
+	second := spec.Phases[4].(*kypo.TrainingPhase)
+	second.DecisionMatrix = second.DecisionMatrix[:1]
+
+	content, err := spec.Content()
+	require.NoError(t, err)
+
+	expected := kypo.ValidationErrors{
+		{Path: "state", Message: `unknown state "DRAFT"`},
+		{Path: "phases[2].phase_relations[0].phase_order", Message: "phase 1 is not a training phase"},
+		{Path: "phases[2].phase_relations[1].question_orders[0]", Message: "question 3 does not exist"},
+		{Path: "phases[3].tasks[0].answer", Message: "invalid regular expression: error parsing regexp: missing closing ): `(`"},
+		{Path: "phases[3].tasks[1].answer", Message: "must not be empty"},
+		{Path: "phases[4].decision_matrix", Message: "must have 2 rows, one for each training phase up to this one, got 1"},
+	}
+
+	assert.Equal(t, expected, kypo.ValidateTrainingDefinitionAdaptive(content))
+}
@@ -0,0 +1,288 @@
+package kypo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TrainingEvent is an event emitted by the training services while a participant plays a training run. It is one of
+// *TrainingRunStarted, *TrainingRunResumed, *TrainingRunEnded, *TrainingRunSurrendered, *LevelStarted, *LevelCompleted,
+// *CorrectAnswerSubmitted, *WrongAnswerSubmitted, *HintTaken, *SolutionDisplayed, *AssessmentAnswers
+// or *UnknownTrainingEvent for event types not known to this library.
+type TrainingEvent interface {
+	// Base returns the fields common to all events.
+	Base() *TrainingEventBase
+	isTrainingEvent()
+}
+
+// TrainingEventBase contains the fields common to all events.
+type TrainingEventBase struct {
+	// Type is the type of the event without the Java package, for example `TrainingRunStarted`.
+	Type          string    `json:"-"`
+	Timestamp     time.Time `json:"-"`
+	TrainingRunId int64     `json:"-"`
+	// LevelId is the id of the level, or the phase in adaptive trainings, in which the event happened.
+	LevelId   int64 `json:"-"`
+	UserRefId int64 `json:"-"`
+	// TrainingTime is the time elapsed since the start of the training run.
+	TrainingTime time.Duration `json:"-"`
+}
+
+type TrainingRunStarted struct{ TrainingEventBase }
+
+type TrainingRunResumed struct{ TrainingEventBase }
+
+type TrainingRunEnded struct{ TrainingEventBase }
+
+type TrainingRunSurrendered struct{ TrainingEventBase }
+
+type LevelStarted struct {
+	TrainingEventBase
+	LevelType  string `json:"level_type"`
+	LevelTitle string `json:"level_title"`
+	MaxScore   int64  `json:"max_score"`
+}
+
+type LevelCompleted struct{ TrainingEventBase }
+
+type CorrectAnswerSubmitted struct {
+	TrainingEventBase
+	Answer string `json:"answer_content"`
+}
+
+type WrongAnswerSubmitted struct {
+	TrainingEventBase
+	Answer string `json:"answer_content"`
+	// Count is the number of wrong answers submitted in the level so far.
+	Count int64 `json:"count"`
+}
+
+type HintTaken struct {
+	TrainingEventBase
+	HintId        int64  `json:"hint_id"`
+	HintTitle     string `json:"hint_title"`
+	PenaltyPoints int64  `json:"penalty_points"`
+}
+
+type SolutionDisplayed struct {
+	TrainingEventBase
+	PenaltyPoints int64 `json:"penalty_points"`
+}
+
+type AssessmentAnswers struct {
+	TrainingEventBase
+	Answers string `json:"answers"`
+}
+
+// UnknownTrainingEvent is an event of a type which is not known to this library. All its fields are preserved.
+type UnknownTrainingEvent struct {
+	TrainingEventBase
+	Fields map[string]json.RawMessage `json:"-"`
+}
+
+func (e *TrainingRunStarted) Base() *TrainingEventBase     { return &e.TrainingEventBase }
+func (e *TrainingRunResumed) Base() *TrainingEventBase     { return &e.TrainingEventBase }
+func (e *TrainingRunEnded) Base() *TrainingEventBase       { return &e.TrainingEventBase }
+func (e *TrainingRunSurrendered) Base() *TrainingEventBase { return &e.TrainingEventBase }
+func (e *LevelStarted) Base() *TrainingEventBase           { return &e.TrainingEventBase }
+func (e *LevelCompleted) Base() *TrainingEventBase         { return &e.TrainingEventBase }
+func (e *CorrectAnswerSubmitted) Base() *TrainingEventBase { return &e.TrainingEventBase }
+func (e *WrongAnswerSubmitted) Base() *TrainingEventBase   { return &e.TrainingEventBase }
+func (e *HintTaken) Base() *TrainingEventBase              { return &e.TrainingEventBase }
+func (e *SolutionDisplayed) Base() *TrainingEventBase      { return &e.TrainingEventBase }
+func (e *AssessmentAnswers) Base() *TrainingEventBase      { return &e.TrainingEventBase }
+func (e *UnknownTrainingEvent) Base() *TrainingEventBase   { return &e.TrainingEventBase }
+
+func (*TrainingRunStarted) isTrainingEvent()     {}
+func (*TrainingRunResumed) isTrainingEvent()     {}
+func (*TrainingRunEnded) isTrainingEvent()       {}
+func (*TrainingRunSurrendered) isTrainingEvent() {}
+func (*LevelStarted) isTrainingEvent()           {}
+func (*LevelCompleted) isTrainingEvent()         {}
+func (*CorrectAnswerSubmitted) isTrainingEvent() {}
+func (*WrongAnswerSubmitted) isTrainingEvent()   {}
+func (*HintTaken) isTrainingEvent()              {}
+func (*SolutionDisplayed) isTrainingEvent()      {}
+func (*AssessmentAnswers) isTrainingEvent()      {}
+func (*UnknownTrainingEvent) isTrainingEvent()   {}
+
+// TrainingRunCommand is a command executed by the participant in the sandbox, captured by the sandbox logging stack.
+type TrainingRunCommand struct {
+	Timestamp time.Time `json:"timestamp_str"`
+	Command   string    `json:"cmd"`
+	// CommandType is the source of the command, for example `bash-command` or `msf-command`.
+	CommandType      string `json:"cmd_type"`
+	Hostname         string `json:"hostname"`
+	Ip               string `json:"ip"`
+	Username         string `json:"username"`
+	WorkingDirectory string `json:"wd"`
+}
+
+// TrainingRunHistoryEntry is an entry of the merged history of a training run, exactly one of Event and Command is set.
+type TrainingRunHistoryEntry struct {
+	Timestamp time.Time
+	Event     TrainingEvent
+	Command   *TrainingRunCommand
+}
+
+// ParseTrainingEvent parses a training event in the format stored by the training services.
+func ParseTrainingEvent(data []byte) (TrainingEvent, error) {
+	header := struct {
+		Type          string          `json:"type"`
+		Timestamp     json.RawMessage `json:"timestamp"`
+		TrainingRunId int64           `json:"training_run_id"`
+		Level         int64           `json:"level"`
+		Phase         int64           `json:"phase"`
+		UserRefId     int64           `json:"user_ref_id"`
+		TrainingTime  int64           `json:"training_time"`
+	}{}
+	err := json.Unmarshal(data, &header)
+	if err != nil {
+		return nil, err
+	}
+
+	base := TrainingEventBase{
+		Type:          header.Type[strings.LastIndex(header.Type, ".")+1:],
+		TrainingRunId: header.TrainingRunId,
+		LevelId:       header.Level,
+		UserRefId:     header.UserRefId,
+		TrainingTime:  time.Duration(header.TrainingTime) * time.Millisecond,
+	}
+	if base.LevelId == 0 {
+		base.LevelId = header.Phase
+	}
+	base.Timestamp, err = parseEventTimestamp(header.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	var event TrainingEvent
+	switch base.Type {
+	case "TrainingRunStarted":
+		event = &TrainingRunStarted{}
+	case "TrainingRunResumed":
+		event = &TrainingRunResumed{}
+	case "TrainingRunEnded":
+		event = &TrainingRunEnded{}
+	case "TrainingRunSurrendered":
+		event = &TrainingRunSurrendered{}
+	case "LevelStarted", "PhaseStarted":
+		event = &LevelStarted{}
+	case "LevelCompleted", "PhaseCompleted":
+		event = &LevelCompleted{}
+	case "CorrectAnswerSubmitted":
+		event = &CorrectAnswerSubmitted{}
+	case "WrongAnswerSubmitted":
+		event = &WrongAnswerSubmitted{}
+	case "HintTaken":
+		event = &HintTaken{}
+	case "SolutionDisplayed":
+		event = &SolutionDisplayed{}
+	case "AssessmentAnswers", "QuestionnaireAnswers":
+		event = &AssessmentAnswers{}
+	default:
+		unknown := &UnknownTrainingEvent{}
+		err = json.Unmarshal(data, &unknown.Fields)
+		if err != nil {
+			return nil, err
+		}
+		event = unknown
+	}
+
+	err = json.Unmarshal(data, event)
+	if err != nil {
+		return nil, err
+	}
+	*event.Base() = base
+	return event, nil
+}
+
+// parseEventTimestamp parses a timestamp in milliseconds since the epoch or in the RFC 3339 format.
+func parseEventTimestamp(data json.RawMessage) (time.Time, error) {
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return time.Time{}, nil
+	}
+
+	var milliseconds int64
+	if json.Unmarshal(data, &milliseconds) == nil {
+		return time.UnixMilli(milliseconds).UTC(), nil
+	}
+
+	var timestamp time.Time
+	err := json.Unmarshal(data, &timestamp)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %s", data)
+	}
+	return timestamp, nil
+}
+
+// SortTrainingEvents sorts the events by their timestamp. Events with equal timestamps keep their order.
+func SortTrainingEvents(events []TrainingEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Base().Timestamp.Before(events[j].Base().Timestamp)
+	})
+}
+
+// MergeTrainingRunHistory merges the events and commands of a training run into a single history ordered by time.
+// Events precede commands with equal timestamps.
+func MergeTrainingRunHistory(events []TrainingEvent, commands []TrainingRunCommand) []TrainingRunHistoryEntry {
+	history := make([]TrainingRunHistoryEntry, 0, len(events)+len(commands))
+	for _, event := range events {
+		history = append(history, TrainingRunHistoryEntry{Timestamp: event.Base().Timestamp, Event: event})
+	}
+	for i := range commands {
+		history = append(history, TrainingRunHistoryEntry{Timestamp: commands[i].Timestamp, Command: &commands[i]})
+	}
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].Timestamp.Before(history[j].Timestamp)
+	})
+	return history
+}
+
+// ListTrainingRunEvents lists the events of the training run given by runID, which belongs to the training instance
+// instanceID of the training definition definitionID. The events are ordered by their timestamp.
+func (c *Client) ListTrainingRunEvents(ctx context.Context, definitionID, instanceID, runID int64) ([]TrainingEvent, error) {
+	var rawEvents []json.RawMessage
+	err := c.doJSONRequest(ctx, http.MethodGet, fmt.Sprintf("%s/kypo-elasticsearch-service/api/v1/training-platform-events/training-definitions/%d/training-instances/%d/training-runs/%d",
+		c.Endpoint, definitionID, instanceID, runID), nil, &rawEvents, http.StatusOK, "training run events", runID)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]TrainingEvent, 0, len(rawEvents))
+	for _, rawEvent := range rawEvents {
+		event, err := ParseTrainingEvent(rawEvent)
+		if err != nil {
+			return nil, &Error{ResourceName: "training run events", Identifier: runID, Err: err}
+		}
+		events = append(events, event)
+	}
+	SortTrainingEvents(events)
+	return events, nil
+}
+
+// ListTrainingRunCommands lists the commands executed by the participant in the sandbox of the training run.
+// The commands are ordered by their timestamp.
+func (c *Client) ListTrainingRunCommands(ctx context.Context, run *TrainingRun) ([]TrainingRunCommand, error) {
+	if run.SandboxId == "" {
+		return nil, &Error{ResourceName: "training run commands", Identifier: run.Id, Err: fmt.Errorf("training run has no sandbox")}
+	}
+
+	var commands []TrainingRunCommand
+	err := c.doJSONRequest(ctx, http.MethodGet, fmt.Sprintf("%s/kypo-elasticsearch-service/api/v1/training-platform-commands/sandboxes/%s",
+		c.Endpoint, run.SandboxId), nil, &commands, http.StatusOK, "training run commands", run.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(commands, func(i, j int) bool {
+		return commands[i].Timestamp.Before(commands[j].Timestamp)
+	})
+	return commands, nil
+}
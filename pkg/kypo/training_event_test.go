@@ -0,0 +1,156 @@
+package kypo_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const trainingEventsResponse = `[
+	{"type": "cz.muni.csirt.kypo.events.trainings.HintTaken", "timestamp": 1706778420000, "training_run_id": 4, "level": 42,
+		"user_ref_id": 7, "training_time": 120000, "hint_id": 3, "hint_title": "Tool", "penalty_points": 2},
+	{"type": "cz.muni.csirt.kypo.events.trainings.TrainingRunStarted", "timestamp": 1706778300000, "training_run_id": 4,
+		"level": 41, "user_ref_id": 7, "training_time": 0},
+	{"type": "cz.muni.csirt.kypo.events.trainings.WrongAnswerSubmitted", "timestamp": 1706778480000, "training_run_id": 4,
+		"level": 42, "user_ref_id": 7, "training_time": 180000, "answer_content": "21", "count": 1},
+	{"type": "cz.muni.csirt.kypo.events.trainings.CorrectAnswerSubmitted", "timestamp": 1706778540000, "training_run_id": 4,
+		"level": 42, "user_ref_id": 7, "training_time": 240000, "answer_content": "22"},
+	{"type": "cz.muni.csirt.kypo.events.trainings.LevelStarted", "timestamp": 1706778360000, "training_run_id": 4,
+		"level": 42, "user_ref_id": 7, "training_time": 60000, "level_type": "TRAINING", "level_title": "Scan", "max_score": 10},
+	{"type": "cz.muni.csirt.kypo.events.trainings.CustomEvent", "timestamp": 1706778600000, "training_run_id": 4,
+		"level": 42, "custom": true}]`
+
+func trainingEventBase(eventType string, minute int, levelID int64) kypo.TrainingEventBase {
+	return kypo.TrainingEventBase{
+		Type:          eventType,
+		Timestamp:     time.Date(2024, 2, 1, 9, 5+minute, 0, 0, time.UTC),
+		TrainingRunId: 4,
+		LevelId:       levelID,
+		UserRefId:     7,
+		TrainingTime:  time.Duration(minute) * time.Minute,
+	}
+}
+
+func TestListTrainingRunEventsSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+		assert.Equal(t, "/kypo-elasticsearch-service/api/v1/training-platform-events/training-definitions/3/training-instances/1/training-runs/4",
+			request.URL.Path)
+		assert.Equal(t, http.MethodGet, request.Method)
+
+		_, _ = fmt.Fprint(writer, trainingEventsResponse)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	unknownBase := trainingEventBase("CustomEvent", 5, 42)
+	unknownBase.UserRefId = 0
+	unknownBase.TrainingTime = 0
+	expected := []kypo.TrainingEvent{
+		&kypo.TrainingRunStarted{TrainingEventBase: trainingEventBase("TrainingRunStarted", 0, 41)},
+		&kypo.LevelStarted{TrainingEventBase: trainingEventBase("LevelStarted", 1, 42), LevelType: "TRAINING", LevelTitle: "Scan", MaxScore: 10},
+		&kypo.HintTaken{TrainingEventBase: trainingEventBase("HintTaken", 2, 42), HintId: 3, HintTitle: "Tool", PenaltyPoints: 2},
+		&kypo.WrongAnswerSubmitted{TrainingEventBase: trainingEventBase("WrongAnswerSubmitted", 3, 42), Answer: "21", Count: 1},
+		&kypo.CorrectAnswerSubmitted{TrainingEventBase: trainingEventBase("CorrectAnswerSubmitted", 4, 42), Answer: "22"},
+	}
+
+	actual, err := c.ListTrainingRunEvents(context.Background(), 3, 1, 4)
+
+	assert.NoError(t, err)
+	require.Len(t, actual, 6)
+	assert.Equal(t, expected, actual[:5])
+	unknown, ok := actual[5].(*kypo.UnknownTrainingEvent)
+	require.True(t, ok)
+	assert.Equal(t, unknownBase, unknown.TrainingEventBase)
+	assert.Equal(t, "true", string(unknown.Fields["custom"]))
+}
+
+func TestListTrainingRunEventsNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.Error{
+		ResourceName: "training run events",
+		Identifier:   int64(4),
+		Err:          kypo.ErrNotFound,
+	}
+
+	actual, err := c.ListTrainingRunEvents(context.Background(), 3, 1, 4)
+
+	assert.Nil(t, actual)
+	assert.Equal(t, expected, err)
+}
+
+func TestParseTrainingEventInvalidTimestamp(t *testing.T) {
+	actual, err := kypo.ParseTrainingEvent([]byte(`{"type": "TrainingRunStarted", "timestamp": true}`))
+
+	assert.Nil(t, actual)
+	assert.EqualError(t, err, "invalid timestamp true")
+}
+
+func TestListTrainingRunCommandsSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+		assert.Equal(t, "/kypo-elasticsearch-service/api/v1/training-platform-commands/sandboxes/6b1e2c3d", request.URL.Path)
+		assert.Equal(t, http.MethodGet, request.Method)
+
+		_, _ = fmt.Fprint(writer, `[
+			{"timestamp_str": "2024-02-01T09:08:30Z", "cmd": "ssh root@10.1.0.5", "cmd_type": "bash-command", "hostname": "attacker",
+				"ip": "10.1.0.2", "username": "kali", "wd": "/home/kali"},
+			{"timestamp_str": "2024-02-01T09:07:10Z", "cmd": "nmap 10.1.0.5", "cmd_type": "bash-command", "hostname": "attacker",
+				"ip": "10.1.0.2", "username": "kali", "wd": "/home/kali"}]`)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := []kypo.TrainingRunCommand{
+		{Timestamp: time.Date(2024, 2, 1, 9, 7, 10, 0, time.UTC), Command: "nmap 10.1.0.5", CommandType: "bash-command",
+			Hostname: "attacker", Ip: "10.1.0.2", Username: "kali", WorkingDirectory: "/home/kali"},
+		{Timestamp: time.Date(2024, 2, 1, 9, 8, 30, 0, time.UTC), Command: "ssh root@10.1.0.5", CommandType: "bash-command",
+			Hostname: "attacker", Ip: "10.1.0.2", Username: "kali", WorkingDirectory: "/home/kali"},
+	}
+
+	actual, err := c.ListTrainingRunCommands(context.Background(), &expectedTrainingRun)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestListTrainingRunCommandsNoSandbox(t *testing.T) {
+	c := kypo.Client{}
+
+	actual, err := c.ListTrainingRunCommands(context.Background(), &kypo.TrainingRun{Id: 4})
+
+	assert.Nil(t, actual)
+	assert.EqualError(t, err, "resource training run commands 4: training run has no sandbox")
+}
+
+func TestMergeTrainingRunHistory(t *testing.T) {
+	started := &kypo.TrainingRunStarted{TrainingEventBase: trainingEventBase("TrainingRunStarted", 0, 41)}
+	correct := &kypo.CorrectAnswerSubmitted{TrainingEventBase: trainingEventBase("CorrectAnswerSubmitted", 4, 42), Answer: "22"}
+	commands := []kypo.TrainingRunCommand{
+		{Timestamp: time.Date(2024, 2, 1, 9, 7, 0, 0, time.UTC), Command: "nmap 10.1.0.5"},
+		{Timestamp: time.Date(2024, 2, 1, 9, 9, 0, 0, time.UTC), Command: "ssh root@10.1.0.5"},
+	}
+
+	actual := kypo.MergeTrainingRunHistory([]kypo.TrainingEvent{started, correct}, commands)
+
+	assert.Equal(t, []kypo.TrainingRunHistoryEntry{
+		{Timestamp: started.Timestamp, Event: started},
+		{Timestamp: commands[0].Timestamp, Command: &commands[0]},
+		{Timestamp: correct.Timestamp, Event: correct},
+		{Timestamp: commands[1].Timestamp, Command: &commands[1]},
+	}, actual)
+}
@@ -0,0 +1,206 @@
+package kypo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// TrainingInstance is a scheduled run of a linear or adaptive training definition, which participants
+// join using the AccessToken.
+type TrainingInstance struct {
+	Id                   int64     `json:"id" tfsdk:"id"`
+	Title                string    `json:"title" tfsdk:"title"`
+	StartTime            time.Time `json:"start_time" tfsdk:"start_time"`
+	EndTime              time.Time `json:"end_time" tfsdk:"end_time"`
+	AccessToken          string    `json:"access_token" tfsdk:"access_token"`
+	TrainingDefinitionId int64     `json:"training_definition_id" tfsdk:"training_definition_id"`
+	// PoolId is the id of the sandbox pool assigned to the instance, it is 0 if no pool is assigned.
+	PoolId              int64  `json:"pool_id" tfsdk:"pool_id"`
+	SandboxDefinitionId int64  `json:"sandbox_definition_id" tfsdk:"sandbox_definition_id"`
+	LocalEnvironment    bool   `json:"local_environment" tfsdk:"local_environment"`
+	Notes               string `json:"notes" tfsdk:"notes"`
+	Organizers          []User `json:"organizers" tfsdk:"organizers"`
+}
+
+// TrainingInstanceRequest contains the editable fields of a training instance
+// used by CreateTrainingInstance and UpdateTrainingInstance.
+type TrainingInstanceRequest struct {
+	Title     string    `json:"title"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	// AccessTokenPrefix is the prefix of the access token, KYPO appends a generated suffix to it.
+	AccessTokenPrefix    string `json:"access_token"`
+	TrainingDefinitionId int64  `json:"training_definition_id"`
+	// SandboxDefinitionId is used only by instances with LocalEnvironment.
+	SandboxDefinitionId int64  `json:"sandbox_definition_id,omitempty"`
+	LocalEnvironment    bool   `json:"local_environment"`
+	Notes               string `json:"notes,omitempty"`
+}
+
+// trainingInstanceResponse is a training instance in the format returned by the training services.
+type trainingInstanceResponse struct {
+	TrainingInstance
+	TrainingDefinition struct {
+		Id int64 `json:"id"`
+	} `json:"training_definition"`
+	Organizers []userRef `json:"organizers"`
+}
+
+func (r *trainingInstanceResponse) toTrainingInstance() *TrainingInstance {
+	instance := r.TrainingInstance
+	instance.TrainingDefinitionId = r.TrainingDefinition.Id
+	instance.Organizers = make([]User, 0, len(r.Organizers))
+	for _, organizer := range r.Organizers {
+		instance.Organizers = append(instance.Organizers, organizer.toUser())
+	}
+	return &instance
+}
+
+// CreateTrainingInstance creates a linear training instance.
+func (c *Client) CreateTrainingInstance(ctx context.Context, request TrainingInstanceRequest) (*TrainingInstance, error) {
+	return c.createTrainingInstance(ctx, "kypo-rest-training", "training instance", request)
+}
+
+// GetTrainingInstance reads the linear training instance given by instanceID.
+func (c *Client) GetTrainingInstance(ctx context.Context, instanceID int64) (*TrainingInstance, error) {
+	return c.getTrainingInstance(ctx, "kypo-rest-training", "training instance", instanceID)
+}
+
+// UpdateTrainingInstance updates the linear training instance given by instanceID and returns the updated instance.
+// KYPO generates a new access token from the AccessTokenPrefix unless it equals the current access token.
+func (c *Client) UpdateTrainingInstance(ctx context.Context, instanceID int64, request TrainingInstanceRequest) (*TrainingInstance, error) {
+	return c.updateTrainingInstance(ctx, "kypo-rest-training", "training instance", instanceID, request)
+}
+
+// DeleteTrainingInstance deletes the linear training instance given by instanceID.
+// If force is false, KYPO refuses to delete an instance which has training runs or an assigned pool.
+func (c *Client) DeleteTrainingInstance(ctx context.Context, instanceID int64, force bool) error {
+	return c.deleteTrainingInstance(ctx, "kypo-rest-training", "training instance", instanceID, force)
+}
+
+// ListTrainingInstances lists all linear training instances.
+func (c *Client) ListTrainingInstances(ctx context.Context) ([]TrainingInstance, error) {
+	return c.listTrainingInstances(ctx, "kypo-rest-training", "training instances")
+}
+
+// AddTrainingInstanceOrganizers adds the users given by userIDs to the organizers of the linear training instance
+// given by instanceID.
+func (c *Client) AddTrainingInstanceOrganizers(ctx context.Context, instanceID int64, userIDs ...int64) error {
+	organizersURL := fmt.Sprintf("%s/kypo-rest-training/api/v1/training-instances/%d/organizers", c.Endpoint, instanceID)
+	return c.editUserRefs(ctx, organizersURL, "organizersAddition", userIDs, "training instance organizers", instanceID)
+}
+
+// RemoveTrainingInstanceOrganizers removes the users given by userIDs from the organizers of the linear training instance
+// given by instanceID.
+func (c *Client) RemoveTrainingInstanceOrganizers(ctx context.Context, instanceID int64, userIDs ...int64) error {
+	organizersURL := fmt.Sprintf("%s/kypo-rest-training/api/v1/training-instances/%d/organizers", c.Endpoint, instanceID)
+	return c.editUserRefs(ctx, organizersURL, "organizersRemoval", userIDs, "training instance organizers", instanceID)
+}
+
+// CreateTrainingInstanceAdaptive creates an adaptive training instance.
+func (c *Client) CreateTrainingInstanceAdaptive(ctx context.Context, request TrainingInstanceRequest) (*TrainingInstance, error) {
+	return c.createTrainingInstance(ctx, "kypo-adaptive-training", "training instance adaptive", request)
+}
+
+// GetTrainingInstanceAdaptive reads the adaptive training instance given by instanceID.
+func (c *Client) GetTrainingInstanceAdaptive(ctx context.Context, instanceID int64) (*TrainingInstance, error) {
+	return c.getTrainingInstance(ctx, "kypo-adaptive-training", "training instance adaptive", instanceID)
+}
+
+// UpdateTrainingInstanceAdaptive updates the adaptive training instance given by instanceID and returns the updated instance.
+// KYPO generates a new access token from the AccessTokenPrefix unless it equals the current access token.
+func (c *Client) UpdateTrainingInstanceAdaptive(ctx context.Context, instanceID int64, request TrainingInstanceRequest) (*TrainingInstance, error) {
+	return c.updateTrainingInstance(ctx, "kypo-adaptive-training", "training instance adaptive", instanceID, request)
+}
+
+// DeleteTrainingInstanceAdaptive deletes the adaptive training instance given by instanceID.
+// If force is false, KYPO refuses to delete an instance which has training runs or an assigned pool.
+func (c *Client) DeleteTrainingInstanceAdaptive(ctx context.Context, instanceID int64, force bool) error {
+	return c.deleteTrainingInstance(ctx, "kypo-adaptive-training", "training instance adaptive", instanceID, force)
+}
+
+// ListTrainingInstancesAdaptive lists all adaptive training instances.
+func (c *Client) ListTrainingInstancesAdaptive(ctx context.Context) ([]TrainingInstance, error) {
+	return c.listTrainingInstances(ctx, "kypo-adaptive-training", "training instances adaptive")
+}
+
+// AddTrainingInstanceAdaptiveOrganizers adds the users given by userIDs to the organizers of the adaptive training
+// instance given by instanceID.
+func (c *Client) AddTrainingInstanceAdaptiveOrganizers(ctx context.Context, instanceID int64, userIDs ...int64) error {
+	organizersURL := fmt.Sprintf("%s/kypo-adaptive-training/api/v1/training-instances/%d/organizers", c.Endpoint, instanceID)
+	return c.editUserRefs(ctx, organizersURL, "organizersAddition", userIDs, "training instance organizers", instanceID)
+}
+
+// RemoveTrainingInstanceAdaptiveOrganizers removes the users given by userIDs from the organizers of the adaptive
+// training instance given by instanceID.
+func (c *Client) RemoveTrainingInstanceAdaptiveOrganizers(ctx context.Context, instanceID int64, userIDs ...int64) error {
+	organizersURL := fmt.Sprintf("%s/kypo-adaptive-training/api/v1/training-instances/%d/organizers", c.Endpoint, instanceID)
+	return c.editUserRefs(ctx, organizersURL, "organizersRemoval", userIDs, "training instance organizers", instanceID)
+}
+
+func (c *Client) createTrainingInstance(ctx context.Context, service, resourceName string, request TrainingInstanceRequest) (*TrainingInstance, error) {
+	var instance trainingInstanceResponse
+	err := c.doJSONRequest(ctx, http.MethodPost, fmt.Sprintf("%s/%s/api/v1/training-instances", c.Endpoint, service),
+		request, &instance, http.StatusOK, resourceName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return instance.toTrainingInstance(), nil
+}
+
+func (c *Client) getTrainingInstance(ctx context.Context, service, resourceName string, instanceID int64) (*TrainingInstance, error) {
+	var instance trainingInstanceResponse
+	err := c.doJSONRequest(ctx, http.MethodGet, fmt.Sprintf("%s/%s/api/v1/training-instances/%d", c.Endpoint, service, instanceID),
+		nil, &instance, http.StatusOK, resourceName, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return instance.toTrainingInstance(), nil
+}
+
+func (c *Client) updateTrainingInstance(ctx context.Context, service, resourceName string, instanceID int64, request TrainingInstanceRequest) (*TrainingInstance, error) {
+	body := struct {
+		Id int64 `json:"id"`
+		TrainingInstanceRequest
+	}{instanceID, request}
+
+	err := c.doJSONRequest(ctx, http.MethodPut, fmt.Sprintf("%s/%s/api/v1/training-instances", c.Endpoint, service),
+		body, nil, http.StatusOK, resourceName, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.getTrainingInstance(ctx, service, resourceName, instanceID)
+}
+
+func (c *Client) deleteTrainingInstance(ctx context.Context, service, resourceName string, instanceID int64, force bool) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/%s/api/v1/training-instances/%d?forceDelete=%s",
+		c.Endpoint, service, instanceID, boolToString(force)), nil)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = c.doRequestWithRetry(req, http.StatusOK, resourceName, instanceID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) listTrainingInstances(ctx context.Context, service, resourceName string) ([]TrainingInstance, error) {
+	responses, err := getAllJavaPages[trainingInstanceResponse](ctx, c, fmt.Sprintf("%s/%s/api/v1/training-instances", c.Endpoint, service), resourceName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]TrainingInstance, 0, len(responses))
+	for _, response := range responses {
+		instances = append(instances, *response.toTrainingInstance())
+	}
+	return instances, nil
+}
@@ -0,0 +1,161 @@
+package kypo
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// AccessTokenPrefix returns the prefix of the access token of a training instance.
+// KYPO creates access tokens by appending a dash and a generated number to the prefix, for example `class-1-1234`.
+func AccessTokenPrefix(accessToken string) string {
+	i := strings.LastIndex(accessToken, "-")
+	if i < 0 || i == len(accessToken)-1 {
+		return accessToken
+	}
+	for _, r := range accessToken[i+1:] {
+		if r < '0' || r > '9' {
+			return accessToken
+		}
+	}
+	return accessToken[:i]
+}
+
+// ValidateAccessTokenPrefix checks the access token prefix without a KYPO instance.
+// It returns nil if no problems were found.
+func ValidateAccessTokenPrefix(prefix string) ValidationErrors {
+	var errs ValidationErrors
+	if prefix == "" {
+		errs.add("access_token", "must not be empty")
+	}
+	if strings.IndexFunc(prefix, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) >= 0 {
+		errs.add("access_token", "must not contain whitespace or control characters")
+	}
+	return errs
+}
+
+// GetTrainingInstanceAccessToken reads the access token of the linear training instance given by instanceID.
+func (c *Client) GetTrainingInstanceAccessToken(ctx context.Context, instanceID int64) (string, error) {
+	instance, err := c.GetTrainingInstance(ctx, instanceID)
+	if err != nil {
+		return "", err
+	}
+	return instance.AccessToken, nil
+}
+
+// RegenerateTrainingInstanceAccessToken updates the linear training instance given by instanceID with the access token
+// prefix and returns the new access token generated by KYPO. The prefix must not be empty. It may be the current
+// prefix, KYPO then generates a new suffix for it.
+func (c *Client) RegenerateTrainingInstanceAccessToken(ctx context.Context, instanceID int64, prefix string) (string, error) {
+	return c.regenerateTrainingInstanceAccessToken(ctx, "kypo-rest-training", "training instance", instanceID, prefix)
+}
+
+// ValidateTrainingInstanceAccessToken finds the linear training instance with the access token. An error wrapping
+// ErrNotFound is returned if there is no such instance. If the instance is not running at the moment,
+// an Error wrapping ErrTrainingInstanceNotRunning with the id of the instance as its Identifier is returned.
+//
+// The check is best-effort: KYPO has no lookup by access token, so only the instances listed for the user
+// of the client are searched. An instance of another organizer is reported as not found.
+func (c *Client) ValidateTrainingInstanceAccessToken(ctx context.Context, accessToken string) (*TrainingInstance, error) {
+	return c.validateTrainingInstanceAccessToken(ctx, "kypo-rest-training", "training instance", "training instances", accessToken)
+}
+
+// IsAccessTokenAvailable checks that no linear training instance uses the access token prefix,
+// so it can be used to create a new instance.
+//
+// The check is best-effort: only the instances listed for the user of the client are searched, and an instance
+// created with the prefix after the check is not detected.
+func (c *Client) IsAccessTokenAvailable(ctx context.Context, prefix string) (bool, error) {
+	return c.isAccessTokenAvailable(ctx, "kypo-rest-training", "training instances", prefix)
+}
+
+// GetTrainingInstanceAdaptiveAccessToken reads the access token of the adaptive training instance given by instanceID.
+func (c *Client) GetTrainingInstanceAdaptiveAccessToken(ctx context.Context, instanceID int64) (string, error) {
+	instance, err := c.GetTrainingInstanceAdaptive(ctx, instanceID)
+	if err != nil {
+		return "", err
+	}
+	return instance.AccessToken, nil
+}
+
+// RegenerateTrainingInstanceAdaptiveAccessToken updates the adaptive training instance given by instanceID with
+// the access token prefix and returns the new access token generated by KYPO, like RegenerateTrainingInstanceAccessToken.
+func (c *Client) RegenerateTrainingInstanceAdaptiveAccessToken(ctx context.Context, instanceID int64, prefix string) (string, error) {
+	return c.regenerateTrainingInstanceAccessToken(ctx, "kypo-adaptive-training", "training instance adaptive", instanceID, prefix)
+}
+
+// ValidateTrainingInstanceAdaptiveAccessToken finds the adaptive training instance with the access token,
+// like ValidateTrainingInstanceAccessToken. The check is best-effort in the same way.
+func (c *Client) ValidateTrainingInstanceAdaptiveAccessToken(ctx context.Context, accessToken string) (*TrainingInstance, error) {
+	return c.validateTrainingInstanceAccessToken(ctx, "kypo-adaptive-training", "training instance adaptive", "training instances adaptive", accessToken)
+}
+
+// IsAccessTokenAvailableAdaptive checks that no adaptive training instance uses the access token prefix,
+// so it can be used to create a new instance. The check is best-effort like IsAccessTokenAvailable.
+func (c *Client) IsAccessTokenAvailableAdaptive(ctx context.Context, prefix string) (bool, error) {
+	return c.isAccessTokenAvailable(ctx, "kypo-adaptive-training", "training instances adaptive", prefix)
+}
+
+func (c *Client) regenerateTrainingInstanceAccessToken(ctx context.Context, service, resourceName string, instanceID int64, prefix string) (string, error) {
+	if errs := ValidateAccessTokenPrefix(prefix); errs != nil {
+		return "", &Error{ResourceName: resourceName, Identifier: instanceID, Err: errs}
+	}
+
+	instance, err := c.getTrainingInstance(ctx, service, resourceName, instanceID)
+	if err != nil {
+		return "", err
+	}
+
+	updated, err := c.updateTrainingInstance(ctx, service, resourceName, instanceID, TrainingInstanceRequest{
+		Title:                instance.Title,
+		StartTime:            instance.StartTime,
+		EndTime:              instance.EndTime,
+		AccessTokenPrefix:    prefix,
+		TrainingDefinitionId: instance.TrainingDefinitionId,
+		SandboxDefinitionId:  instance.SandboxDefinitionId,
+		LocalEnvironment:     instance.LocalEnvironment,
+		Notes:                instance.Notes,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return updated.AccessToken, nil
+}
+
+func (c *Client) validateTrainingInstanceAccessToken(ctx context.Context, service, resourceName, listResourceName, accessToken string) (*TrainingInstance, error) {
+	instances, err := c.listTrainingInstances(ctx, service, listResourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range instances {
+		instance := &instances[i]
+		if instance.AccessToken != accessToken {
+			continue
+		}
+
+		now := time.Now()
+		if now.Before(instance.StartTime) || now.After(instance.EndTime) {
+			return nil, &Error{ResourceName: resourceName, Identifier: instance.Id, Err: ErrTrainingInstanceNotRunning}
+		}
+		return instance, nil
+	}
+
+	return nil, &Error{ResourceName: resourceName, Identifier: accessToken, Err: ErrNotFound}
+}
+
+func (c *Client) isAccessTokenAvailable(ctx context.Context, service, resourceName, prefix string) (bool, error) {
+	instances, err := c.listTrainingInstances(ctx, service, resourceName)
+	if err != nil {
+		return false, err
+	}
+
+	for _, instance := range instances {
+		if AccessTokenPrefix(instance.AccessToken) == prefix {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,234 @@
+package kypo_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// trainingInstancesServer serves a single page with the given training instances.
+func trainingInstancesServer(t *testing.T, service string, instances ...string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+		assert.Equal(t, fmt.Sprintf("/%s/api/v1/training-instances", service), request.URL.Path)
+		assert.Equal(t, http.MethodGet, request.Method)
+
+		_, _ = fmt.Fprintf(writer, `{"content": [%s], "pagination": {"number": 0, "number_of_elements": %d, "size": 100,
+			"total_elements": %d, "total_pages": 1}}`, strings.Join(instances, ","), len(instances), len(instances))
+	}))
+}
+
+func TestAccessTokenPrefix(t *testing.T) {
+	assert.Equal(t, "class-1", kypo.AccessTokenPrefix("class-1-1234"))
+	assert.Equal(t, "class", kypo.AccessTokenPrefix("class-1234"))
+	assert.Equal(t, "class-a", kypo.AccessTokenPrefix("class-a"))
+	assert.Equal(t, "class-", kypo.AccessTokenPrefix("class-"))
+	assert.Equal(t, "class", kypo.AccessTokenPrefix("class"))
+}
+
+func TestValidateAccessTokenPrefix(t *testing.T) {
+	assert.Nil(t, kypo.ValidateAccessTokenPrefix("class-1"))
+	assert.EqualError(t, kypo.ValidateAccessTokenPrefix(""), "access_token: must not be empty")
+	assert.EqualError(t, kypo.ValidateAccessTokenPrefix("class 1"), "access_token: must not contain whitespace or control characters")
+}
+
+func TestGetTrainingInstanceAccessTokenSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertTrainingInstanceGet(t, request, "kypo-rest-training")
+
+		_, _ = fmt.Fprint(writer, trainingInstanceResponse)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.GetTrainingInstanceAccessToken(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "class-1-1234", actual)
+}
+
+func TestRegenerateTrainingInstanceAccessTokenSuccessful(t *testing.T) {
+	updated := false
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch request.Method {
+		case http.MethodGet:
+			assertTrainingInstanceGet(t, request, "kypo-rest-training")
+			if updated {
+				_, _ = fmt.Fprint(writer, `{"id": 1, "access_token": "class-2-5678"}`)
+				return
+			}
+			_, _ = fmt.Fprint(writer, trainingInstanceResponse)
+		case http.MethodPut:
+			assert.Equal(t, "/kypo-rest-training/api/v1/training-instances", request.URL.Path)
+			body, _ := io.ReadAll(request.Body)
+			assert.JSONEq(t, `{"id": 1, "title": "Class 1", "start_time": "2024-02-01T09:00:00Z", "end_time": "2024-02-01T12:00:00Z",
+				"access_token": "class-2", "training_definition_id": 3, "local_environment": false, "notes": "notes"}`, string(body))
+			updated = true
+			_, _ = fmt.Fprint(writer, "class-2-5678")
+		}
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.RegenerateTrainingInstanceAccessToken(context.Background(), 1, "class-2")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "class-2-5678", actual)
+}
+
+func TestRegenerateTrainingInstanceAccessTokenCurrentPrefix(t *testing.T) {
+	updated := false
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method == http.MethodPut {
+			body, _ := io.ReadAll(request.Body)
+			var instance map[string]any
+			assert.NoError(t, json.Unmarshal(body, &instance))
+			assert.Equal(t, "class-1", instance["access_token"])
+			updated = true
+			return
+		}
+		if updated {
+			_, _ = fmt.Fprint(writer, `{"id": 1, "access_token": "class-1-9012"}`)
+			return
+		}
+		_, _ = fmt.Fprint(writer, trainingInstanceResponse)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.RegenerateTrainingInstanceAccessToken(context.Background(), 1, "class-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "class-1-9012", actual)
+}
+
+func TestRegenerateTrainingInstanceAccessTokenEmptyPrefix(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		t.Errorf("unexpected request %s %s", request.Method, request.URL.Path)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.RegenerateTrainingInstanceAccessToken(context.Background(), 1, "")
+
+	assert.Empty(t, actual)
+	assert.EqualError(t, err, "resource training instance 1: access_token: must not be empty")
+}
+
+func TestRegenerateTrainingInstanceAccessTokenInvalidPrefix(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertTrainingInstanceGet(t, request, "kypo-rest-training")
+		_, _ = fmt.Fprint(writer, trainingInstanceResponse)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.RegenerateTrainingInstanceAccessToken(context.Background(), 1, "class 2")
+
+	assert.Empty(t, actual)
+	assert.EqualError(t, err, "resource training instance 1: access_token: must not contain whitespace or control characters")
+}
+
+func TestValidateTrainingInstanceAccessTokenRunning(t *testing.T) {
+	running := fmt.Sprintf(`{"id": 2, "access_token": "class-2-5678", "start_time": "%s", "end_time": "%s"}`,
+		time.Now().Add(-time.Hour).UTC().Format(time.RFC3339), time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+	ts := trainingInstancesServer(t, "kypo-rest-training", trainingInstanceResponse, running)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.ValidateTrainingInstanceAccessToken(context.Background(), "class-2-5678")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), actual.Id)
+}
+
+func TestValidateTrainingInstanceAccessTokenNotRunning(t *testing.T) {
+	ts := trainingInstancesServer(t, "kypo-rest-training", trainingInstanceResponse)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.Error{
+		ResourceName: "training instance",
+		Identifier:   int64(1),
+		Err:          kypo.ErrTrainingInstanceNotRunning,
+	}
+
+	actual, err := c.ValidateTrainingInstanceAccessToken(context.Background(), "class-1-1234")
+
+	assert.Nil(t, actual)
+	assert.Equal(t, expected, err)
+}
+
+func TestValidateTrainingInstanceAccessTokenNotFound(t *testing.T) {
+	ts := trainingInstancesServer(t, "kypo-rest-training", trainingInstanceResponse)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.Error{
+		ResourceName: "training instance",
+		Identifier:   "class-1-9999",
+		Err:          kypo.ErrNotFound,
+	}
+
+	actual, err := c.ValidateTrainingInstanceAccessToken(context.Background(), "class-1-9999")
+
+	assert.Nil(t, actual)
+	assert.Equal(t, expected, err)
+}
+
+func TestIsAccessTokenAvailable(t *testing.T) {
+	ts := trainingInstancesServer(t, "kypo-rest-training", trainingInstanceResponse)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	available, err := c.IsAccessTokenAvailable(context.Background(), "class-1")
+	assert.NoError(t, err)
+	assert.False(t, available)
+
+	available, err = c.IsAccessTokenAvailable(context.Background(), "class")
+	assert.NoError(t, err)
+	assert.True(t, available)
+}
+
+func TestIsAccessTokenAvailableAdaptive(t *testing.T) {
+	ts := trainingInstancesServer(t, "kypo-adaptive-training", trainingInstanceResponse)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	available, err := c.IsAccessTokenAvailableAdaptive(context.Background(), "class-1")
+
+	assert.NoError(t, err)
+	assert.False(t, available)
+}
+
+func TestIsAccessTokenAvailableServerError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	available, err := c.IsAccessTokenAvailable(context.Background(), "class-1")
+
+	assert.False(t, available)
+	assert.Equal(t, &kypo.Error{ResourceName: "training instances", Identifier: "", Err: fmt.Errorf("status: 500, body: ")}, err)
+}
@@ -0,0 +1,31 @@
+package kypo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ExportTrainingInstanceArchive streams the zip archive of the linear training instance given by instanceID to w.
+// The archive contains the training instance, its training definition, training runs and their events, it can be
+// parsed by the archive package. The archive is not buffered in memory, so large archives can be written to a file.
+// The number of written bytes is returned.
+func (c *Client) ExportTrainingInstanceArchive(ctx context.Context, instanceID int64, w io.Writer) (int64, error) {
+	return c.exportTrainingInstanceArchive(ctx, "kypo-rest-training", "training instance", instanceID, w)
+}
+
+// ExportTrainingInstanceArchiveAdaptive streams the zip archive of the adaptive training instance given by instanceID
+// to w, like ExportTrainingInstanceArchive.
+func (c *Client) ExportTrainingInstanceArchiveAdaptive(ctx context.Context, instanceID int64, w io.Writer) (int64, error) {
+	return c.exportTrainingInstanceArchive(ctx, "kypo-adaptive-training", "training instance adaptive", instanceID, w)
+}
+
+func (c *Client) exportTrainingInstanceArchive(ctx context.Context, service, resourceName string, instanceID int64, w io.Writer) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/%s/api/v1/exports/training-instances/%d", c.Endpoint, service, instanceID), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return c.doStreamRequest(req, w, http.StatusOK, resourceName, instanceID)
+}
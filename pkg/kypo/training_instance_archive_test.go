@@ -0,0 +1,97 @@
+package kypo_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExportTrainingInstanceArchiveSuccessful(t *testing.T) {
+	archive := bytes.Repeat([]byte("PK\x03\x04archive"), 100000)
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+		assert.Equal(t, "/kypo-rest-training/api/v1/exports/training-instances/1", request.URL.Path)
+		assert.Equal(t, http.MethodGet, request.Method)
+
+		writer.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = writer.Write(archive)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+	buffer := bytes.Buffer{}
+
+	written, err := c.ExportTrainingInstanceArchive(context.Background(), 1, &buffer)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(archive)), written)
+	assert.Equal(t, archive, buffer.Bytes())
+}
+
+func TestExportTrainingInstanceArchiveNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+	buffer := bytes.Buffer{}
+
+	expected := &kypo.Error{
+		ResourceName: "training instance",
+		Identifier:   int64(1),
+		Err:          kypo.ErrNotFound,
+	}
+
+	written, err := c.ExportTrainingInstanceArchive(context.Background(), 1, &buffer)
+
+	assert.Equal(t, expected, err)
+	assert.Equal(t, int64(0), written)
+	assert.Empty(t, buffer.Bytes())
+}
+
+func TestExportTrainingInstanceArchiveRetry(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		attempts++
+		if attempts == 1 {
+			writer.WriteHeader(http.StatusBadGateway)
+			_, _ = fmt.Fprint(writer, "bad gateway")
+			return
+		}
+		_, _ = fmt.Fprint(writer, "archive")
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+	c.RetryCount = 1
+	buffer := bytes.Buffer{}
+
+	_, err := c.ExportTrainingInstanceArchive(context.Background(), 1, &buffer)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "archive", buffer.String())
+}
+
+func TestExportTrainingInstanceArchiveAdaptiveSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-adaptive-training/api/v1/exports/training-instances/1", request.URL.Path)
+
+		_, _ = fmt.Fprint(writer, "archive")
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+	buffer := bytes.Buffer{}
+
+	written, err := c.ExportTrainingInstanceArchiveAdaptive(context.Background(), 1, &buffer)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), written)
+	assert.Equal(t, "archive", buffer.String())
+}
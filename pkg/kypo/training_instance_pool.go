@@ -0,0 +1,119 @@
+package kypo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// AssignPoolToTrainingInstance assigns the sandbox pool given by poolID to the linear training instance given by instanceID.
+// The `sandbox_definition_id` of the training definition of the instance, or the SandboxDefinitionId of the instance
+// if the training definition does not set it, must match the definition of the pool, otherwise an Error wrapping
+// ErrSandboxDefinitionMismatch is returned. If neither is set, an Error wrapping ErrSandboxDefinitionUnknown is returned.
+// KYPO locks the pool on assignment, the returned pool is read again, so its LockId is set.
+func (c *Client) AssignPoolToTrainingInstance(ctx context.Context, instanceID, poolID int64) (*SandboxPool, error) {
+	return c.assignPoolToTrainingInstance(ctx, "kypo-rest-training", "training instance", instanceID, poolID)
+}
+
+// UnassignPoolFromTrainingInstance unassigns the sandbox pool from the linear training instance given by instanceID.
+// KYPO unlocks the pool, the returned pool is read again, so its LockId is 0. If there is no pool assigned,
+// an Error wrapping ErrNoPoolAssigned is returned.
+func (c *Client) UnassignPoolFromTrainingInstance(ctx context.Context, instanceID int64) (*SandboxPool, error) {
+	return c.unassignPoolFromTrainingInstance(ctx, "kypo-rest-training", "training instance", instanceID)
+}
+
+// AssignPoolToTrainingInstanceAdaptive assigns the sandbox pool given by poolID to the adaptive training instance
+// given by instanceID. It behaves like AssignPoolToTrainingInstance.
+func (c *Client) AssignPoolToTrainingInstanceAdaptive(ctx context.Context, instanceID, poolID int64) (*SandboxPool, error) {
+	return c.assignPoolToTrainingInstance(ctx, "kypo-adaptive-training", "training instance adaptive", instanceID, poolID)
+}
+
+// UnassignPoolFromTrainingInstanceAdaptive unassigns the sandbox pool from the adaptive training instance
+// given by instanceID. It behaves like UnassignPoolFromTrainingInstance.
+func (c *Client) UnassignPoolFromTrainingInstanceAdaptive(ctx context.Context, instanceID int64) (*SandboxPool, error) {
+	return c.unassignPoolFromTrainingInstance(ctx, "kypo-adaptive-training", "training instance adaptive", instanceID)
+}
+
+func (c *Client) assignPoolToTrainingInstance(ctx context.Context, service, resourceName string, instanceID, poolID int64) (*SandboxPool, error) {
+	instance, err := c.getTrainingInstance(ctx, service, resourceName, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := c.GetSandboxPool(ctx, poolID)
+	if err != nil {
+		return nil, err
+	}
+
+	sandboxDefinitionID, err := c.getTrainingDefinitionSandboxDefinitionID(ctx, service, resourceName, instance.TrainingDefinitionId)
+	if err != nil {
+		return nil, err
+	}
+	if sandboxDefinitionID == 0 {
+		sandboxDefinitionID = instance.SandboxDefinitionId
+	}
+	if sandboxDefinitionID == 0 {
+		return nil, &Error{ResourceName: resourceName, Identifier: instanceID, Err: fmt.Errorf(
+			"%w: training definition %d does not set sandbox_definition_id", ErrSandboxDefinitionUnknown, instance.TrainingDefinitionId)}
+	}
+	if sandboxDefinitionID != pool.Definition.Id {
+		return nil, &Error{ResourceName: resourceName, Identifier: instanceID, Err: fmt.Errorf(
+			"%w: pool %d uses sandbox definition %d, training definition %d uses sandbox definition %d",
+			ErrSandboxDefinitionMismatch, poolID, pool.Definition.Id, instance.TrainingDefinitionId, sandboxDefinitionID)}
+	}
+
+	request := struct {
+		PoolId int64 `json:"pool_id"`
+	}{poolID}
+	err = c.doJSONRequest(ctx, http.MethodPatch, fmt.Sprintf("%s/%s/api/v1/training-instances/%d/assign-pool", c.Endpoint, service, instanceID),
+		request, nil, http.StatusOK, resourceName, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetSandboxPool(ctx, poolID)
+}
+
+func (c *Client) unassignPoolFromTrainingInstance(ctx context.Context, service, resourceName string, instanceID int64) (*SandboxPool, error) {
+	instance, err := c.getTrainingInstance(ctx, service, resourceName, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	if instance.PoolId == 0 {
+		return nil, &Error{ResourceName: resourceName, Identifier: instanceID, Err: ErrNoPoolAssigned}
+	}
+
+	err = c.doJSONRequest(ctx, http.MethodPatch, fmt.Sprintf("%s/%s/api/v1/training-instances/%d/unassign-pool", c.Endpoint, service, instanceID),
+		nil, nil, http.StatusOK, resourceName, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetSandboxPool(ctx, instance.PoolId)
+}
+
+// getTrainingDefinitionSandboxDefinitionID reads the `sandbox_definition_id` of the exported training definition,
+// which is 0 when it is not set.
+func (c *Client) getTrainingDefinitionSandboxDefinitionID(ctx context.Context, service, resourceName string, definitionID int64) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/%s/api/v1/exports/training-definitions/%d", c.Endpoint, service, definitionID), nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("accept", "application/octet-stream")
+
+	body, _, err := c.doRequestWithRetry(req, http.StatusOK, resourceName, fmt.Sprintf("training definition %d", definitionID))
+	if err != nil {
+		return 0, err
+	}
+
+	definition := struct {
+		SandboxDefinitionId int64 `json:"sandbox_definition_id"`
+	}{}
+	err = json.Unmarshal(body, &definition)
+	if err != nil {
+		return 0, err
+	}
+
+	return definition.SandboxDefinitionId, nil
+}
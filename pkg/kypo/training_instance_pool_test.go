@@ -0,0 +1,195 @@
+package kypo_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// trainingInstancePoolServer serves a training instance with the pool 2 and the training definition 3,
+// which uses the given sandbox definition. The pool is locked after it is assigned.
+func trainingInstancePoolServer(t *testing.T, service string, sandboxDefinitionID int64, requests *[]string) *httptest.Server {
+	locked := false
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+		key := request.Method + " " + request.URL.Path
+		*requests = append(*requests, key)
+
+		switch key {
+		case fmt.Sprintf("GET /%s/api/v1/training-instances/1", service):
+			_, _ = fmt.Fprint(writer, trainingInstanceResponse)
+		case fmt.Sprintf("GET /%s/api/v1/exports/training-definitions/3", service):
+			_, _ = fmt.Fprintf(writer, `{"title": "title", "sandbox_definition_id": %d}`, sandboxDefinitionID)
+		case fmt.Sprintf("PATCH /%s/api/v1/training-instances/1/assign-pool", service):
+			body, _ := io.ReadAll(request.Body)
+			assert.JSONEq(t, `{"pool_id": 2}`, string(body))
+			locked = true
+			_, _ = fmt.Fprint(writer, `{"id": 1, "pool_id": 2}`)
+		case fmt.Sprintf("PATCH /%s/api/v1/training-instances/1/unassign-pool", service):
+			locked = false
+			_, _ = fmt.Fprint(writer, `{"id": 1, "pool_id": null}`)
+		case "GET /kypo-sandbox-service/api/v1/pools/2":
+			pool := sandboxPoolResponse
+			pool.Id = 2
+			if locked {
+				lockId := 5
+				pool.LockId = &lockId
+			}
+			response, _ := json.Marshal(pool)
+			_, _ = fmt.Fprint(writer, string(response))
+		default:
+			t.Errorf("unexpected request %s", key)
+			writer.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+}
+
+func TestAssignPoolToTrainingInstanceSuccessful(t *testing.T) {
+	var requests []string
+	ts := trainingInstancePoolServer(t, "kypo-rest-training", 1, &requests)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := expectedPoolResponse
+	expected.Id = 2
+	expected.LockId = 5
+
+	actual, err := c.AssignPoolToTrainingInstance(context.Background(), 1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expected, actual)
+	assert.Equal(t, []string{
+		"GET /kypo-rest-training/api/v1/training-instances/1",
+		"GET /kypo-sandbox-service/api/v1/pools/2",
+		"GET /kypo-rest-training/api/v1/exports/training-definitions/3",
+		"PATCH /kypo-rest-training/api/v1/training-instances/1/assign-pool",
+		"GET /kypo-sandbox-service/api/v1/pools/2",
+	}, requests)
+}
+
+func TestAssignPoolToTrainingInstanceWithoutSandboxDefinition(t *testing.T) {
+	var requests []string
+	ts := trainingInstancePoolServer(t, "kypo-rest-training", 0, &requests)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.AssignPoolToTrainingInstance(context.Background(), 1, 2)
+
+	assert.Nil(t, actual)
+	assert.ErrorIs(t, err, kypo.ErrSandboxDefinitionUnknown)
+	assert.EqualError(t, err, "resource training instance 1: sandbox definition of the training instance is unknown: "+
+		"training definition 3 does not set sandbox_definition_id")
+	assert.NotContains(t, requests, "PATCH /kypo-rest-training/api/v1/training-instances/1/assign-pool")
+}
+
+func TestAssignPoolToTrainingInstanceMismatch(t *testing.T) {
+	var requests []string
+	ts := trainingInstancePoolServer(t, "kypo-rest-training", 7, &requests)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.AssignPoolToTrainingInstance(context.Background(), 1, 2)
+
+	assert.Nil(t, actual)
+	assert.ErrorIs(t, err, kypo.ErrSandboxDefinitionMismatch)
+	assert.EqualError(t, err, "resource training instance 1: sandbox definition of the pool does not match the training definition: "+
+		"pool 2 uses sandbox definition 1, training definition 3 uses sandbox definition 7")
+	assert.NotContains(t, requests, "PATCH /kypo-rest-training/api/v1/training-instances/1/assign-pool")
+}
+
+func TestAssignPoolToTrainingInstanceNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.Error{
+		ResourceName: "training instance",
+		Identifier:   int64(1),
+		Err:          kypo.ErrNotFound,
+	}
+
+	actual, err := c.AssignPoolToTrainingInstance(context.Background(), 1, 2)
+
+	assert.Nil(t, actual)
+	assert.Equal(t, expected, err)
+}
+
+func TestUnassignPoolFromTrainingInstanceSuccessful(t *testing.T) {
+	var requests []string
+	ts := trainingInstancePoolServer(t, "kypo-rest-training", 1, &requests)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := expectedPoolResponse
+	expected.Id = 2
+
+	actual, err := c.UnassignPoolFromTrainingInstance(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expected, actual)
+	assert.Equal(t, []string{
+		"GET /kypo-rest-training/api/v1/training-instances/1",
+		"PATCH /kypo-rest-training/api/v1/training-instances/1/unassign-pool",
+		"GET /kypo-sandbox-service/api/v1/pools/2",
+	}, requests)
+}
+
+func TestUnassignPoolFromTrainingInstanceNoPool(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertTrainingInstanceGet(t, request, "kypo-rest-training")
+		_, _ = fmt.Fprint(writer, `{"id": 1, "title": "Class 1", "pool_id": null}`)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.Error{
+		ResourceName: "training instance",
+		Identifier:   int64(1),
+		Err:          kypo.ErrNoPoolAssigned,
+	}
+
+	actual, err := c.UnassignPoolFromTrainingInstance(context.Background(), 1)
+
+	assert.Nil(t, actual)
+	assert.Equal(t, expected, err)
+}
+
+func TestAssignPoolToTrainingInstanceAdaptiveSuccessful(t *testing.T) {
+	var requests []string
+	ts := trainingInstancePoolServer(t, "kypo-adaptive-training", 1, &requests)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.AssignPoolToTrainingInstanceAdaptive(context.Background(), 1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), actual.LockId)
+}
+
+func TestUnassignPoolFromTrainingInstanceAdaptiveSuccessful(t *testing.T) {
+	var requests []string
+	ts := trainingInstancePoolServer(t, "kypo-adaptive-training", 1, &requests)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.UnassignPoolFromTrainingInstanceAdaptive(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), actual.LockId)
+}
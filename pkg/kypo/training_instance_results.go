@@ -0,0 +1,233 @@
+package kypo
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// TrainingInstanceResults are the results of the participants of a training instance.
+type TrainingInstanceResults struct {
+	TrainingInstanceId int64               `json:"training_instance_id"`
+	Participants       []ParticipantResult `json:"participants"`
+}
+
+// ParticipantResult is the result of a single training run. The totals are summed over the levels.
+type ParticipantResult struct {
+	TrainingRunId   int64         `json:"training_run_id"`
+	Participant     User          `json:"participant"`
+	Finished        bool          `json:"finished"`
+	TrainingScore   int64         `json:"training_score"`
+	AssessmentScore int64         `json:"assessment_score"`
+	HintsTaken      int64         `json:"hints_taken"`
+	WrongAnswers    int64         `json:"wrong_answers"`
+	Duration        time.Duration `json:"-"`
+	Levels          []LevelResult `json:"levels"`
+}
+
+// LevelResult is the result of a participant in a single level, or a phase of adaptive trainings.
+type LevelResult struct {
+	LevelId int64  `json:"level_id"`
+	Order   int64  `json:"order"`
+	Title   string `json:"title"`
+	// Type is the LevelType, or the PhaseType of adaptive trainings.
+	Type              string        `json:"type"`
+	Score             int64         `json:"score"`
+	HintsTaken        int64         `json:"hints_taken"`
+	WrongAnswers      int64         `json:"wrong_answers"`
+	SolutionDisplayed bool          `json:"solution_displayed"`
+	Duration          time.Duration `json:"-"`
+}
+
+type trainingInstanceResultsResponse []struct {
+	TrainingRunId int64   `json:"training_run_id"`
+	Participant   userRef `json:"participant"`
+	Finished      bool    `json:"finished"`
+	Levels        []struct {
+		Id                int64  `json:"id"`
+		Order             int64  `json:"order"`
+		Title             string `json:"title"`
+		LevelType         string `json:"level_type"`
+		PhaseType         string `json:"phase_type"`
+		Score             int64  `json:"score"`
+		HintsTaken        int64  `json:"hints_taken"`
+		WrongAnswers      int64  `json:"wrong_answers"`
+		SolutionDisplayed bool   `json:"solution_displayed"`
+		// Duration is in milliseconds.
+		Duration int64 `json:"duration"`
+	} `json:"levels"`
+}
+
+func (r trainingInstanceResultsResponse) toResults(instanceID int64) *TrainingInstanceResults {
+	results := TrainingInstanceResults{
+		TrainingInstanceId: instanceID,
+		Participants:       make([]ParticipantResult, 0, len(r)),
+	}
+	for _, run := range r {
+		participant := ParticipantResult{
+			TrainingRunId: run.TrainingRunId,
+			Participant:   run.Participant.toUser(),
+			Finished:      run.Finished,
+			Levels:        make([]LevelResult, 0, len(run.Levels)),
+		}
+		for _, level := range run.Levels {
+			result := LevelResult{
+				LevelId:           level.Id,
+				Order:             level.Order,
+				Title:             level.Title,
+				Type:              level.LevelType,
+				Score:             level.Score,
+				HintsTaken:        level.HintsTaken,
+				WrongAnswers:      level.WrongAnswers,
+				SolutionDisplayed: level.SolutionDisplayed,
+				Duration:          time.Duration(level.Duration) * time.Millisecond,
+			}
+			if result.Type == "" {
+				result.Type = level.PhaseType
+			}
+
+			if result.Type == string(LevelTypeAssessment) || result.Type == string(PhaseTypeQuestionnaire) {
+				participant.AssessmentScore += result.Score
+			} else {
+				participant.TrainingScore += result.Score
+			}
+			participant.HintsTaken += result.HintsTaken
+			participant.WrongAnswers += result.WrongAnswers
+			participant.Duration += result.Duration
+			participant.Levels = append(participant.Levels, result)
+		}
+		sort.SliceStable(participant.Levels, func(i, j int) bool {
+			return participant.Levels[i].Order < participant.Levels[j].Order
+		})
+		results.Participants = append(results.Participants, participant)
+	}
+	return &results
+}
+
+// GetTrainingInstanceResults reads the results of the participants of the linear training instance given by instanceID.
+func (c *Client) GetTrainingInstanceResults(ctx context.Context, instanceID int64) (*TrainingInstanceResults, error) {
+	return c.getTrainingInstanceResults(ctx, "kypo-rest-training", instanceID)
+}
+
+// GetTrainingInstanceResultsAdaptive reads the results of the participants of the adaptive training instance given
+// by instanceID. The results of questionnaire phases are counted as the assessment score.
+func (c *Client) GetTrainingInstanceResultsAdaptive(ctx context.Context, instanceID int64) (*TrainingInstanceResults, error) {
+	return c.getTrainingInstanceResults(ctx, "kypo-adaptive-training", instanceID)
+}
+
+func (c *Client) getTrainingInstanceResults(ctx context.Context, service string, instanceID int64) (*TrainingInstanceResults, error) {
+	var response trainingInstanceResultsResponse
+	err := c.doJSONRequest(ctx, http.MethodGet, fmt.Sprintf("%s/%s/api/v1/visualizations/training-instances/%d/table", c.Endpoint, service, instanceID),
+		nil, &response, http.StatusOK, "training instance results", instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.toResults(instanceID), nil
+}
+
+// WriteJSON writes the results as indented JSON. Durations are written in seconds.
+func (r *TrainingInstanceResults) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteCSV writes the results as CSV with a row per participant. The totals are followed by the score, duration,
+// hints taken and wrong answers of each level, in the order of the levels.
+func (r *TrainingInstanceResults) WriteCSV(w io.Writer) error {
+	var orders []int64
+	seen := map[int64]bool{}
+	for _, participant := range r.Participants {
+		for _, level := range participant.Levels {
+			if !seen[level.Order] {
+				seen[level.Order] = true
+				orders = append(orders, level.Order)
+			}
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i] < orders[j] })
+
+	header := []string{"training_run_id", "user_id", "sub", "full_name", "mail", "finished", "training_score",
+		"assessment_score", "hints_taken", "wrong_answers", "duration_seconds"}
+	for _, order := range orders {
+		header = append(header,
+			fmt.Sprintf("level_%d_score", order),
+			fmt.Sprintf("level_%d_duration_seconds", order),
+			fmt.Sprintf("level_%d_hints_taken", order),
+			fmt.Sprintf("level_%d_wrong_answers", order))
+	}
+
+	writer := csv.NewWriter(w)
+	err := writer.Write(header)
+	if err != nil {
+		return err
+	}
+
+	for _, participant := range r.Participants {
+		row := []string{
+			strconv.FormatInt(participant.TrainingRunId, 10),
+			strconv.FormatInt(participant.Participant.Id, 10),
+			participant.Participant.Sub,
+			participant.Participant.FullName,
+			participant.Participant.Mail,
+			strconv.FormatBool(participant.Finished),
+			strconv.FormatInt(participant.TrainingScore, 10),
+			strconv.FormatInt(participant.AssessmentScore, 10),
+			strconv.FormatInt(participant.HintsTaken, 10),
+			strconv.FormatInt(participant.WrongAnswers, 10),
+			formatSeconds(participant.Duration),
+		}
+		levels := make(map[int64]LevelResult, len(participant.Levels))
+		for _, level := range participant.Levels {
+			levels[level.Order] = level
+		}
+		for _, order := range orders {
+			level, ok := levels[order]
+			if !ok {
+				row = append(row, "", "", "", "")
+				continue
+			}
+			row = append(row,
+				strconv.FormatInt(level.Score, 10),
+				formatSeconds(level.Duration),
+				strconv.FormatInt(level.HintsTaken, 10),
+				strconv.FormatInt(level.WrongAnswers, 10))
+		}
+
+		err = writer.Write(row)
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func (p ParticipantResult) MarshalJSON() ([]byte, error) {
+	type plain ParticipantResult
+	return marshalJSON(struct {
+		plain
+		DurationSeconds float64 `json:"duration_seconds"`
+	}{plain(p), p.Duration.Seconds()})
+}
+
+func (l LevelResult) MarshalJSON() ([]byte, error) {
+	type plain LevelResult
+	return marshalJSON(struct {
+		plain
+		DurationSeconds float64 `json:"duration_seconds"`
+	}{plain(l), l.Duration.Seconds()})
+}
+
+func formatSeconds(duration time.Duration) string {
+	return strconv.FormatFloat(duration.Seconds(), 'f', -1, 64)
+}
@@ -0,0 +1,182 @@
+package kypo_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const trainingInstanceResultsResponse = `[
+	{"training_run_id": 4, "finished": true, "participant": {"user_ref_id": 7, "sub": "student", "full_name": "Student, First"},
+		"levels": [
+			{"id": 43, "order": 1, "title": "Quiz", "level_type": "ASSESSMENT_LEVEL", "score": 5, "duration": 30000},
+			{"id": 42, "order": 0, "title": "Scan the network", "level_type": "TRAINING_LEVEL", "score": 8, "hints_taken": 1,
+				"wrong_answers": 2, "duration": 90500}]},
+	{"training_run_id": 5, "finished": false, "participant": {"user_ref_id": 8, "sub": "late"},
+		"levels": [{"id": 42, "order": 0, "title": "Scan the network", "level_type": "TRAINING_LEVEL", "score": 0,
+			"solution_displayed": true, "duration": 60000}]}]`
+
+var expectedTrainingInstanceResults = kypo.TrainingInstanceResults{
+	TrainingInstanceId: 1,
+	Participants: []kypo.ParticipantResult{
+		{
+			TrainingRunId:   4,
+			Participant:     kypo.User{Id: 7, Sub: "student", FullName: "Student, First"},
+			Finished:        true,
+			TrainingScore:   8,
+			AssessmentScore: 5,
+			HintsTaken:      1,
+			WrongAnswers:    2,
+			Duration:        120500 * time.Millisecond,
+			Levels: []kypo.LevelResult{
+				{LevelId: 42, Order: 0, Title: "Scan the network", Type: "TRAINING_LEVEL", Score: 8, HintsTaken: 1,
+					WrongAnswers: 2, Duration: 90500 * time.Millisecond},
+				{LevelId: 43, Order: 1, Title: "Quiz", Type: "ASSESSMENT_LEVEL", Score: 5, Duration: 30 * time.Second},
+			},
+		},
+		{
+			TrainingRunId: 5,
+			Participant:   kypo.User{Id: 8, Sub: "late"},
+			Duration:      time.Minute,
+			Levels: []kypo.LevelResult{
+				{LevelId: 42, Order: 0, Title: "Scan the network", Type: "TRAINING_LEVEL", SolutionDisplayed: true,
+					Duration: time.Minute},
+			},
+		},
+	},
+}
+
+func TestGetTrainingInstanceResultsSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+		assert.Equal(t, "/kypo-rest-training/api/v1/visualizations/training-instances/1/table", request.URL.Path)
+		assert.Equal(t, http.MethodGet, request.Method)
+
+		_, _ = fmt.Fprint(writer, trainingInstanceResultsResponse)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.GetTrainingInstanceResults(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expectedTrainingInstanceResults, actual)
+}
+
+func TestGetTrainingInstanceResultsAdaptiveSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+		assert.Equal(t, "/kypo-adaptive-training/api/v1/visualizations/training-instances/1/table", request.URL.Path)
+		assert.Equal(t, http.MethodGet, request.Method)
+
+		_, _ = fmt.Fprint(writer, `[{"training_run_id": 4, "finished": true, "participant": {"user_ref_id": 7},
+			"levels": [{"id": 43, "order": 0, "title": "Survey", "phase_type": "QUESTIONNAIRE", "score": 3},
+				{"id": 44, "order": 1, "title": "Exploit", "phase_type": "TRAINING", "score": 10}]}]`)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.TrainingInstanceResults{
+		TrainingInstanceId: 1,
+		Participants: []kypo.ParticipantResult{{
+			TrainingRunId:   4,
+			Participant:     kypo.User{Id: 7},
+			Finished:        true,
+			TrainingScore:   10,
+			AssessmentScore: 3,
+			Levels: []kypo.LevelResult{
+				{LevelId: 43, Order: 0, Title: "Survey", Type: "QUESTIONNAIRE", Score: 3},
+				{LevelId: 44, Order: 1, Title: "Exploit", Type: "TRAINING", Score: 10},
+			},
+		}},
+	}
+
+	actual, err := c.GetTrainingInstanceResultsAdaptive(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestGetTrainingInstanceResultsNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.Error{
+		ResourceName: "training instance results",
+		Identifier:   int64(1),
+		Err:          kypo.ErrNotFound,
+	}
+
+	actual, err := c.GetTrainingInstanceResults(context.Background(), 1)
+
+	assert.Nil(t, actual)
+	assert.Equal(t, expected, err)
+}
+
+func TestTrainingInstanceResultsWriteCSV(t *testing.T) {
+	buffer := bytes.Buffer{}
+
+	err := expectedTrainingInstanceResults.WriteCSV(&buffer)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "training_run_id,user_id,sub,full_name,mail,finished,training_score,assessment_score,hints_taken,"+
+		"wrong_answers,duration_seconds,level_0_score,level_0_duration_seconds,level_0_hints_taken,level_0_wrong_answers,"+
+		"level_1_score,level_1_duration_seconds,level_1_hints_taken,level_1_wrong_answers\n"+
+		"4,7,student,\"Student, First\",,true,8,5,1,2,120.5,8,90.5,1,2,5,30,0,0\n"+
+		"5,8,late,,,false,0,0,0,0,60,0,60,0,0,,,,\n", buffer.String())
+}
+
+func TestTrainingInstanceResultsWriteJSON(t *testing.T) {
+	buffer := bytes.Buffer{}
+
+	err := expectedTrainingInstanceResults.WriteJSON(&buffer)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"training_instance_id": 1, "participants": [
+		{"training_run_id": 4, "finished": true, "training_score": 8, "assessment_score": 5, "hints_taken": 1,
+			"wrong_answers": 2, "duration_seconds": 120.5,
+			"participant": {"id": 7, "sub": "student", "full_name": "Student, First", "given_name": "", "family_name": "", "mail": ""},
+			"levels": [
+				{"level_id": 42, "order": 0, "title": "Scan the network", "type": "TRAINING_LEVEL", "score": 8, "hints_taken": 1,
+					"wrong_answers": 2, "solution_displayed": false, "duration_seconds": 90.5},
+				{"level_id": 43, "order": 1, "title": "Quiz", "type": "ASSESSMENT_LEVEL", "score": 5, "hints_taken": 0,
+					"wrong_answers": 0, "solution_displayed": false, "duration_seconds": 30}]},
+		{"training_run_id": 5, "finished": false, "training_score": 0, "assessment_score": 0, "hints_taken": 0,
+			"wrong_answers": 0, "duration_seconds": 60,
+			"participant": {"id": 8, "sub": "late", "full_name": "", "given_name": "", "family_name": "", "mail": ""},
+			"levels": [
+				{"level_id": 42, "order": 0, "title": "Scan the network", "type": "TRAINING_LEVEL", "score": 0, "hints_taken": 0,
+					"wrong_answers": 0, "solution_displayed": true, "duration_seconds": 60}]}]}`, buffer.String())
+}
+
+func TestTrainingInstanceResultsWriteJSONNotEscaped(t *testing.T) {
+	results := kypo.TrainingInstanceResults{
+		TrainingInstanceId: 1,
+		Participants: []kypo.ParticipantResult{
+			{
+				TrainingRunId: 4,
+				Participant:   kypo.User{Id: 7, Sub: "student", FullName: "<Student> & co"},
+				Levels:        []kypo.LevelResult{{LevelId: 42, Title: "Scan <network> & hosts"}},
+			},
+		},
+	}
+	buffer := bytes.Buffer{}
+
+	err := results.WriteJSON(&buffer)
+
+	assert.NoError(t, err)
+	assert.Contains(t, buffer.String(), `"full_name": "<Student> & co"`)
+	assert.Contains(t, buffer.String(), `"title": "Scan <network> & hosts"`)
+}
@@ -0,0 +1,307 @@
+package kypo_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var (
+	trainingInstanceResponse = `{"id": 1, "title": "Class 1", "start_time": "2024-02-01T09:00:00Z", "end_time": "2024-02-01T12:00:00Z",
+		"access_token": "class-1-1234", "pool_id": 2, "sandbox_definition_id": null, "local_environment": false,
+		"backward_mode": false, "notes": "notes", "sandboxes_with_training_run": [],
+		"training_definition": {"id": 3, "title": "Junior hacker", "state": "RELEASED"},
+		"organizers": [{"user_ref_id": 1, "sub": "kypo-admin", "full_name": "Admin", "given_name": "A", "family_name": "Dmin"}]}`
+
+	expectedTrainingInstance = kypo.TrainingInstance{
+		Id:                   1,
+		Title:                "Class 1",
+		StartTime:            time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC),
+		EndTime:              time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC),
+		AccessToken:          "class-1-1234",
+		TrainingDefinitionId: 3,
+		PoolId:               2,
+		Notes:                "notes",
+		Organizers: []kypo.User{
+			{Id: 1, Sub: "kypo-admin", FullName: "Admin", GivenName: "A", FamilyName: "Dmin"},
+		},
+	}
+
+	trainingInstanceRequest = kypo.TrainingInstanceRequest{
+		Title:                "Class 1",
+		StartTime:            time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC),
+		EndTime:              time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC),
+		AccessTokenPrefix:    "class-1",
+		TrainingDefinitionId: 3,
+		Notes:                "notes",
+	}
+)
+
+func assertTrainingInstanceGet(t *testing.T, request *http.Request, service string) {
+	assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
+	assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+	assert.Equal(t, fmt.Sprintf("/%s/api/v1/training-instances/1", service), request.URL.Path)
+	assert.Equal(t, http.MethodGet, request.Method)
+}
+
+func TestCreateTrainingInstanceSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+		assert.Equal(t, "/kypo-rest-training/api/v1/training-instances", request.URL.Path)
+		assert.Equal(t, http.MethodPost, request.Method)
+
+		body, _ := io.ReadAll(request.Body)
+		assert.JSONEq(t, `{"title": "Class 1", "start_time": "2024-02-01T09:00:00Z", "end_time": "2024-02-01T12:00:00Z",
+			"access_token": "class-1", "training_definition_id": 3, "local_environment": false, "notes": "notes"}`, string(body))
+
+		_, _ = fmt.Fprint(writer, trainingInstanceResponse)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.CreateTrainingInstance(context.Background(), trainingInstanceRequest)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expectedTrainingInstance, actual)
+}
+
+func TestCreateTrainingInstanceServerError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.Error{
+		ResourceName: "training instance",
+		Identifier:   "",
+		Err:          fmt.Errorf("status: 500, body: "),
+	}
+
+	actual, err := c.CreateTrainingInstance(context.Background(), trainingInstanceRequest)
+
+	assert.Nil(t, actual)
+	assert.Equal(t, expected, err)
+}
+
+func TestGetTrainingInstanceSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertTrainingInstanceGet(t, request, "kypo-rest-training")
+
+		_, _ = fmt.Fprint(writer, trainingInstanceResponse)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.GetTrainingInstance(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expectedTrainingInstance, actual)
+}
+
+func TestGetTrainingInstanceNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertTrainingInstanceGet(t, request, "kypo-rest-training")
+
+		writer.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.Error{
+		ResourceName: "training instance",
+		Identifier:   int64(1),
+		Err:          kypo.ErrNotFound,
+	}
+
+	actual, err := c.GetTrainingInstance(context.Background(), 1)
+
+	assert.Nil(t, actual)
+	assert.Equal(t, expected, err)
+}
+
+func TestUpdateTrainingInstanceSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method == http.MethodGet {
+			assertTrainingInstanceGet(t, request, "kypo-rest-training")
+			_, _ = fmt.Fprint(writer, trainingInstanceResponse)
+			return
+		}
+		assert.Equal(t, "/kypo-rest-training/api/v1/training-instances", request.URL.Path)
+		assert.Equal(t, http.MethodPut, request.Method)
+
+		body := struct {
+			Id    int64  `json:"id"`
+			Title string `json:"title"`
+		}{}
+		assert.NoError(t, json.NewDecoder(request.Body).Decode(&body))
+		assert.Equal(t, int64(1), body.Id)
+		assert.Equal(t, "Class 1", body.Title)
+
+		_, _ = fmt.Fprint(writer, "class-1-1234")
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.UpdateTrainingInstance(context.Background(), 1, trainingInstanceRequest)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expectedTrainingInstance, actual)
+}
+
+func TestDeleteTrainingInstanceSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-rest-training/api/v1/training-instances/1", request.URL.Path)
+		assert.Equal(t, http.MethodDelete, request.Method)
+		assert.Equal(t, "true", request.URL.Query().Get("forceDelete"))
+
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	err := c.DeleteTrainingInstance(context.Background(), 1, true)
+
+	assert.NoError(t, err)
+}
+
+func TestDeleteTrainingInstanceNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "false", request.URL.Query().Get("forceDelete"))
+
+		writer.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.Error{
+		ResourceName: "training instance",
+		Identifier:   int64(1),
+		Err:          kypo.ErrNotFound,
+	}
+
+	err := c.DeleteTrainingInstance(context.Background(), 1, false)
+
+	assert.Equal(t, expected, err)
+}
+
+func TestListTrainingInstancesSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-rest-training/api/v1/training-instances", request.URL.Path)
+		assert.Equal(t, http.MethodGet, request.Method)
+		assert.Equal(t, "0", request.URL.Query().Get("page"))
+
+		_, _ = fmt.Fprintf(writer, `{"content": [%s], "pagination": {"number": 0, "number_of_elements": 1, "size": 100,
+			"total_elements": 1, "total_pages": 1}}`, trainingInstanceResponse)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.ListTrainingInstances(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []kypo.TrainingInstance{expectedTrainingInstance}, actual)
+}
+
+func TestAddTrainingInstanceOrganizersSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-rest-training/api/v1/training-instances/1/organizers", request.URL.Path)
+		assert.Equal(t, http.MethodPut, request.Method)
+		assert.Equal(t, "4,5", request.URL.Query().Get("organizersAddition"))
+
+		writer.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	err := c.AddTrainingInstanceOrganizers(context.Background(), 1, 4, 5)
+
+	assert.NoError(t, err)
+}
+
+func TestRemoveTrainingInstanceAdaptiveOrganizersSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-adaptive-training/api/v1/training-instances/1/organizers", request.URL.Path)
+		assert.Equal(t, "4", request.URL.Query().Get("organizersRemoval"))
+
+		writer.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	err := c.RemoveTrainingInstanceAdaptiveOrganizers(context.Background(), 1, 4)
+
+	assert.NoError(t, err)
+}
+
+func TestCreateTrainingInstanceAdaptiveSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-adaptive-training/api/v1/training-instances", request.URL.Path)
+		assert.Equal(t, http.MethodPost, request.Method)
+
+		_, _ = fmt.Fprint(writer, trainingInstanceResponse)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.CreateTrainingInstanceAdaptive(context.Background(), trainingInstanceRequest)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expectedTrainingInstance, actual)
+}
+
+func TestGetTrainingInstanceAdaptiveNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assertTrainingInstanceGet(t, request, "kypo-adaptive-training")
+
+		writer.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.Error{
+		ResourceName: "training instance adaptive",
+		Identifier:   int64(1),
+		Err:          kypo.ErrNotFound,
+	}
+
+	actual, err := c.GetTrainingInstanceAdaptive(context.Background(), 1)
+
+	assert.Nil(t, actual)
+	assert.Equal(t, expected, err)
+}
+
+func TestDeleteTrainingInstanceAdaptiveSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-adaptive-training/api/v1/training-instances/1", request.URL.Path)
+		assert.Equal(t, http.MethodDelete, request.Method)
+
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	err := c.DeleteTrainingInstanceAdaptive(context.Background(), 1, false)
+
+	assert.NoError(t, err)
+}
@@ -0,0 +1,157 @@
+package kypo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// TrainingRunState is the state of a training run.
+type TrainingRunState string
+
+const (
+	TrainingRunStateRunning  TrainingRunState = "RUNNING"
+	TrainingRunStateFinished TrainingRunState = "FINISHED"
+	TrainingRunStateArchived TrainingRunState = "ARCHIVED"
+)
+
+// TrainingRun is a playthrough of a training instance by a single participant.
+type TrainingRun struct {
+	Id                 int64            `json:"id" tfsdk:"id"`
+	TrainingInstanceId int64            `json:"instance_id" tfsdk:"instance_id"`
+	State              TrainingRunState `json:"state" tfsdk:"state"`
+	StartTime          time.Time        `json:"start_time" tfsdk:"start_time"`
+	EndTime            time.Time        `json:"end_time" tfsdk:"end_time"`
+	Participant        User             `json:"participant" tfsdk:"participant"`
+	// CurrentLevelId is the id of the level, or the phase in adaptive trainings, the participant is playing.
+	CurrentLevelId int64 `json:"current_level_id" tfsdk:"current_level_id"`
+	// SandboxId is the id of the sandbox assigned to the run, it is empty for runs in a local environment.
+	SandboxId string `json:"sandbox_instance_ref_id" tfsdk:"sandbox_id"`
+	// SandboxAllocationUnitId is the id of the SandboxAllocationUnit of the sandbox assigned to the run.
+	SandboxAllocationUnitId int64 `json:"sandbox_instance_allocation_id" tfsdk:"sandbox_allocation_unit_id"`
+}
+
+// trainingRunResponse is a training run in the format returned by the training services.
+type trainingRunResponse struct {
+	TrainingRun
+	Participant  userRef `json:"participant_ref"`
+	CurrentLevel *struct {
+		Id int64 `json:"id"`
+	} `json:"current_level"`
+	CurrentPhase *struct {
+		Id int64 `json:"id"`
+	} `json:"current_phase"`
+}
+
+func (r *trainingRunResponse) toTrainingRun() *TrainingRun {
+	run := r.TrainingRun
+	run.Participant = r.Participant.toUser()
+	if r.CurrentLevel != nil {
+		run.CurrentLevelId = r.CurrentLevel.Id
+	}
+	if r.CurrentPhase != nil {
+		run.CurrentLevelId = r.CurrentPhase.Id
+	}
+	return &run
+}
+
+// ListTrainingRuns lists all training runs of the linear training instance given by instanceID.
+func (c *Client) ListTrainingRuns(ctx context.Context, instanceID int64) ([]TrainingRun, error) {
+	return c.listTrainingRuns(ctx, "kypo-rest-training", instanceID)
+}
+
+// GetTrainingRun reads the linear training run given by runID.
+func (c *Client) GetTrainingRun(ctx context.Context, runID int64) (*TrainingRun, error) {
+	return c.getTrainingRun(ctx, "kypo-rest-training", "training run", runID)
+}
+
+// DeleteTrainingRun deletes the linear training run given by runID. If force is false, KYPO refuses to delete a running run.
+func (c *Client) DeleteTrainingRun(ctx context.Context, runID int64, force bool) error {
+	return c.deleteTrainingRun(ctx, "kypo-rest-training", "training run", runID, force)
+}
+
+// ArchiveTrainingRun archives the linear training run given by runID, so it is no longer listed to the participant.
+func (c *Client) ArchiveTrainingRun(ctx context.Context, runID int64) error {
+	return c.archiveTrainingRun(ctx, "kypo-rest-training", "training run", runID)
+}
+
+// ListTrainingRunsAdaptive lists all training runs of the adaptive training instance given by instanceID.
+func (c *Client) ListTrainingRunsAdaptive(ctx context.Context, instanceID int64) ([]TrainingRun, error) {
+	return c.listTrainingRuns(ctx, "kypo-adaptive-training", instanceID)
+}
+
+// GetTrainingRunAdaptive reads the adaptive training run given by runID.
+func (c *Client) GetTrainingRunAdaptive(ctx context.Context, runID int64) (*TrainingRun, error) {
+	return c.getTrainingRun(ctx, "kypo-adaptive-training", "training run adaptive", runID)
+}
+
+// DeleteTrainingRunAdaptive deletes the adaptive training run given by runID. If force is false, KYPO refuses
+// to delete a running run.
+func (c *Client) DeleteTrainingRunAdaptive(ctx context.Context, runID int64, force bool) error {
+	return c.deleteTrainingRun(ctx, "kypo-adaptive-training", "training run adaptive", runID, force)
+}
+
+// ArchiveTrainingRunAdaptive archives the adaptive training run given by runID, so it is no longer listed
+// to the participant.
+func (c *Client) ArchiveTrainingRunAdaptive(ctx context.Context, runID int64) error {
+	return c.archiveTrainingRun(ctx, "kypo-adaptive-training", "training run adaptive", runID)
+}
+
+func (c *Client) listTrainingRuns(ctx context.Context, service string, instanceID int64) ([]TrainingRun, error) {
+	responses, err := getAllJavaPages[trainingRunResponse](ctx, c, fmt.Sprintf("%s/%s/api/v1/training-instances/%d/training-runs", c.Endpoint, service, instanceID),
+		"training runs", fmt.Sprintf("training instance %d", instanceID))
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]TrainingRun, 0, len(responses))
+	for _, response := range responses {
+		run := response.toTrainingRun()
+		if run.TrainingInstanceId == 0 {
+			run.TrainingInstanceId = instanceID
+		}
+		runs = append(runs, *run)
+	}
+	return runs, nil
+}
+
+func (c *Client) getTrainingRun(ctx context.Context, service, resourceName string, runID int64) (*TrainingRun, error) {
+	var run trainingRunResponse
+	err := c.doJSONRequest(ctx, http.MethodGet, fmt.Sprintf("%s/%s/api/v1/training-runs/%d", c.Endpoint, service, runID),
+		nil, &run, http.StatusOK, resourceName, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	return run.toTrainingRun(), nil
+}
+
+func (c *Client) deleteTrainingRun(ctx context.Context, service, resourceName string, runID int64, force bool) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/%s/api/v1/training-runs/%d?forceDelete=%s",
+		c.Endpoint, service, runID, boolToString(force)), nil)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = c.doRequestWithRetry(req, http.StatusOK, resourceName, runID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) archiveTrainingRun(ctx context.Context, service, resourceName string, runID int64) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, fmt.Sprintf("%s/%s/api/v1/training-runs/%d/archive", c.Endpoint, service, runID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = c.doRequestWithRetry(req, http.StatusOK, resourceName, runID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,405 @@
+package kypo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// TrainingRunSession plays a linear training run as a participant, using the Client of the participant.
+// A new run is started by Access, an existing run is resumed by setting TrainingRunId.
+type TrainingRunSession struct {
+	Client *Client
+
+	// TrainingRunId is the id of the played training run. It is set by Access.
+	TrainingRunId int64
+
+	// Levels lists the levels of the training in their order. It is set by Access and CurrentLevel.
+	Levels []TrainingRunLevelSummary
+}
+
+// TrainingRunSessionAdaptive plays an adaptive training run as a participant, using the Client of the participant.
+// A new run is started by Access, an existing run is resumed by setting TrainingRunId.
+type TrainingRunSessionAdaptive struct {
+	Client *Client
+
+	// TrainingRunId is the id of the played training run. It is set by Access.
+	TrainingRunId int64
+
+	// Phases lists the phases of the training in their order. It is set by Access and CurrentPhase.
+	Phases []TrainingRunPhaseSummary
+}
+
+// TrainingRunLevel is a level of a linear training as shown to the participant, without answers and solutions.
+type TrainingRunLevel struct {
+	Id        int64     `json:"id"`
+	Title     string    `json:"title"`
+	LevelType LevelType `json:"level_type"`
+	Order     int64     `json:"order"`
+	MaxScore  int64     `json:"max_score"`
+	// Content is the content of info and training levels.
+	Content string `json:"content"`
+	// Instructions are the instructions of assessment levels.
+	Instructions string                `json:"instructions"`
+	CloudContent string                `json:"cloud_content"`
+	LocalContent string                `json:"local_content"`
+	Hints        []TrainingRunHint     `json:"hints"`
+	Questions    []TrainingRunQuestion `json:"questions"`
+}
+
+// TrainingRunPhase is a phase of an adaptive training as shown to the participant, without answers and solutions.
+type TrainingRunPhase struct {
+	Id        int64     `json:"id"`
+	Title     string    `json:"title"`
+	PhaseType PhaseType `json:"phase_type"`
+	Order     int64     `json:"order"`
+	// Content is the content of info phases.
+	Content      string `json:"content"`
+	CloudContent string `json:"cloud_content"`
+	LocalContent string `json:"local_content"`
+	// Task is the task of a training phase, which was selected for the participant.
+	Task      *TrainingRunTask      `json:"task"`
+	Questions []TrainingRunQuestion `json:"questions"`
+}
+
+type TrainingRunTask struct {
+	Id      int64  `json:"id"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	Order   int64  `json:"order"`
+}
+
+type TrainingRunHint struct {
+	Id          int64  `json:"id"`
+	Title       string `json:"title"`
+	Content     string `json:"content"`
+	HintPenalty int64  `json:"hint_penalty"`
+}
+
+type TrainingRunQuestion struct {
+	Id                         int64               `json:"id"`
+	QuestionType               string              `json:"question_type"`
+	Text                       string              `json:"text"`
+	Order                      int64               `json:"order"`
+	AnswerRequired             bool                `json:"answer_required"`
+	Choices                    []TrainingRunChoice `json:"choices"`
+	ExtendedMatchingOptions    []TrainingRunChoice `json:"extended_matching_options"`
+	ExtendedMatchingStatements []TrainingRunChoice `json:"extended_matching_statements"`
+}
+
+// TrainingRunChoice is a choice, extended matching option or extended matching statement of a question.
+type TrainingRunChoice struct {
+	Id    int64  `json:"id"`
+	Text  string `json:"text"`
+	Order int64  `json:"order"`
+}
+
+type TrainingRunLevelSummary struct {
+	Id        int64     `json:"id"`
+	Title     string    `json:"title"`
+	LevelType LevelType `json:"level_type"`
+	Order     int64     `json:"order"`
+}
+
+type TrainingRunPhaseSummary struct {
+	Id        int64     `json:"id"`
+	Title     string    `json:"title"`
+	PhaseType PhaseType `json:"phase_type"`
+	Order     int64     `json:"order"`
+}
+
+// AnswerResult is the evaluation of an answer submitted to a training level or task.
+type AnswerResult struct {
+	Correct           bool  `json:"correct"`
+	RemainingAttempts int64 `json:"remaining_attempts"`
+	// Solution is shown after the participant runs out of attempts.
+	Solution string `json:"solution"`
+}
+
+// QuestionAnswer is an answer to a question of an assessment level or a questionnaire phase.
+type QuestionAnswer struct {
+	QuestionId int64 `json:"question_id"`
+	// Answers are the texts of the chosen choices, or the text of the answer to a free form question.
+	Answers []string `json:"answers"`
+	// ExtendedMatchingPairs pair the extended matching statements with options.
+	ExtendedMatchingPairs []ExtendedMatchingPair `json:"extended_matching_pairs,omitempty"`
+}
+
+type ExtendedMatchingPair struct {
+	StatementOrder int64 `json:"x"`
+	OptionOrder    int64 `json:"y"`
+}
+
+type accessTrainingRunResponse struct {
+	TrainingRunId int64                     `json:"training_run_id"`
+	Level         *TrainingRunLevel         `json:"abstract_level_dto"`
+	Levels        []TrainingRunLevelSummary `json:"info_about_levels"`
+	Phase         *TrainingRunPhase         `json:"abstract_phase_dto"`
+	Phases        []TrainingRunPhaseSummary `json:"info_about_phases"`
+}
+
+// NewTrainingRunSession creates a TrainingRunSession which plays linear training runs using the given client.
+func NewTrainingRunSession(client *Client) *TrainingRunSession {
+	return &TrainingRunSession{Client: client}
+}
+
+// Access starts a new training run, or resumes a running one, of the linear training instance with the given
+// access token and returns its current level.
+func (s *TrainingRunSession) Access(ctx context.Context, accessToken string) (*TrainingRunLevel, error) {
+	response, err := s.Client.accessTrainingRun(ctx, "kypo-rest-training", accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	s.TrainingRunId = response.TrainingRunId
+	s.Levels = response.Levels
+	return response.Level, nil
+}
+
+// CurrentLevel returns the level the participant is playing.
+func (s *TrainingRunSession) CurrentLevel(ctx context.Context) (*TrainingRunLevel, error) {
+	if s.TrainingRunId == 0 {
+		return nil, ErrTrainingRunNotAccessed
+	}
+
+	response, err := s.Client.resumeTrainingRun(ctx, "kypo-rest-training", s.TrainingRunId)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Levels = response.Levels
+	return response.Level, nil
+}
+
+// SubmitAnswer submits the answer to the current training level, or the passkey to the current access level.
+// The answer is sent only once, because the training service records every submitted answer.
+func (s *TrainingRunSession) SubmitAnswer(ctx context.Context, answer string) (*AnswerResult, error) {
+	if s.TrainingRunId == 0 {
+		return nil, ErrTrainingRunNotAccessed
+	}
+	return s.Client.submitTrainingRunAnswer(ctx, "kypo-rest-training", s.TrainingRunId, answer)
+}
+
+// SubmitAssessment submits the answers to the questions of the current assessment level.
+// The answers are sent only once, because the training service accepts them only once.
+func (s *TrainingRunSession) SubmitAssessment(ctx context.Context, answers []QuestionAnswer) error {
+	if s.TrainingRunId == 0 {
+		return ErrTrainingRunNotAccessed
+	}
+	return s.Client.submitTrainingRunQuestions(ctx, "kypo-rest-training", "assessment-evaluations", s.TrainingRunId, answers)
+}
+
+// TakeHint reveals the hint of the current training level given by hintID. The hint penalty is deducted from the score.
+// The request is sent only once, so the penalty is not deducted twice.
+func (s *TrainingRunSession) TakeHint(ctx context.Context, hintID int64) (*TrainingRunHint, error) {
+	if s.TrainingRunId == 0 {
+		return nil, ErrTrainingRunNotAccessed
+	}
+
+	var hint TrainingRunHint
+	err := s.Client.doJSONRequestOnce(ctx, http.MethodGet, fmt.Sprintf("%s/kypo-rest-training/api/v1/training-runs/%d/hints/%d",
+		s.Client.Endpoint, s.TrainingRunId, hintID), nil, &hint, http.StatusOK, "training run", s.TrainingRunId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hint, nil
+}
+
+// TakeSolution reveals the solution of the current training level. The score of the level may be lost.
+// The request is sent only once, like in TakeHint.
+func (s *TrainingRunSession) TakeSolution(ctx context.Context) (string, error) {
+	if s.TrainingRunId == 0 {
+		return "", ErrTrainingRunNotAccessed
+	}
+	return s.Client.getTrainingRunSolution(ctx, "kypo-rest-training", s.TrainingRunId)
+}
+
+// NextLevel moves the participant to the next level and returns it. The current level must be completed.
+// The request is sent only once, so no level is skipped.
+func (s *TrainingRunSession) NextLevel(ctx context.Context) (*TrainingRunLevel, error) {
+	if s.TrainingRunId == 0 {
+		return nil, ErrTrainingRunNotAccessed
+	}
+
+	var level TrainingRunLevel
+	err := s.Client.doJSONRequestOnce(ctx, http.MethodGet, fmt.Sprintf("%s/kypo-rest-training/api/v1/training-runs/%d/next-levels",
+		s.Client.Endpoint, s.TrainingRunId), nil, &level, http.StatusOK, "training run", s.TrainingRunId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &level, nil
+}
+
+// Finish finishes the training run. The last level must be completed.
+func (s *TrainingRunSession) Finish(ctx context.Context) error {
+	if s.TrainingRunId == 0 {
+		return ErrTrainingRunNotAccessed
+	}
+	return s.Client.finishTrainingRun(ctx, "kypo-rest-training", s.TrainingRunId)
+}
+
+// NewTrainingRunSessionAdaptive creates a TrainingRunSessionAdaptive which plays adaptive training runs
+// using the given client.
+func NewTrainingRunSessionAdaptive(client *Client) *TrainingRunSessionAdaptive {
+	return &TrainingRunSessionAdaptive{Client: client}
+}
+
+// Access starts a new training run, or resumes a running one, of the adaptive training instance with the given
+// access token and returns its current phase.
+func (s *TrainingRunSessionAdaptive) Access(ctx context.Context, accessToken string) (*TrainingRunPhase, error) {
+	response, err := s.Client.accessTrainingRun(ctx, "kypo-adaptive-training", accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	s.TrainingRunId = response.TrainingRunId
+	s.Phases = response.Phases
+	return response.Phase, nil
+}
+
+// CurrentPhase returns the phase the participant is playing.
+func (s *TrainingRunSessionAdaptive) CurrentPhase(ctx context.Context) (*TrainingRunPhase, error) {
+	if s.TrainingRunId == 0 {
+		return nil, ErrTrainingRunNotAccessed
+	}
+
+	response, err := s.Client.resumeTrainingRun(ctx, "kypo-adaptive-training", s.TrainingRunId)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Phases = response.Phases
+	return response.Phase, nil
+}
+
+// SubmitAnswer submits the answer to the task of the current training phase, or the passkey to the current access phase.
+// The answer is sent only once, because the training service records every submitted answer.
+func (s *TrainingRunSessionAdaptive) SubmitAnswer(ctx context.Context, answer string) (*AnswerResult, error) {
+	if s.TrainingRunId == 0 {
+		return nil, ErrTrainingRunNotAccessed
+	}
+	return s.Client.submitTrainingRunAnswer(ctx, "kypo-adaptive-training", s.TrainingRunId, answer)
+}
+
+// SubmitQuestionnaire submits the answers to the questions of the current questionnaire phase.
+// The answers are sent only once, because the training service accepts them only once.
+func (s *TrainingRunSessionAdaptive) SubmitQuestionnaire(ctx context.Context, answers []QuestionAnswer) error {
+	if s.TrainingRunId == 0 {
+		return ErrTrainingRunNotAccessed
+	}
+	return s.Client.submitTrainingRunQuestions(ctx, "kypo-adaptive-training", "questionnaire-evaluation", s.TrainingRunId, answers)
+}
+
+// TakeSolution reveals the solution of the task of the current training phase. The request is sent only once.
+func (s *TrainingRunSessionAdaptive) TakeSolution(ctx context.Context) (string, error) {
+	if s.TrainingRunId == 0 {
+		return "", ErrTrainingRunNotAccessed
+	}
+	return s.Client.getTrainingRunSolution(ctx, "kypo-adaptive-training", s.TrainingRunId)
+}
+
+// NextPhase moves the participant to the next phase and returns it. For training phases, the task is selected
+// based on the previous performance of the participant. The request is sent only once, so no phase is skipped.
+func (s *TrainingRunSessionAdaptive) NextPhase(ctx context.Context) (*TrainingRunPhase, error) {
+	if s.TrainingRunId == 0 {
+		return nil, ErrTrainingRunNotAccessed
+	}
+
+	var phase TrainingRunPhase
+	err := s.Client.doJSONRequestOnce(ctx, http.MethodGet, fmt.Sprintf("%s/kypo-adaptive-training/api/v1/training-runs/%d/next-phases",
+		s.Client.Endpoint, s.TrainingRunId), nil, &phase, http.StatusOK, "training run adaptive", s.TrainingRunId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &phase, nil
+}
+
+// Finish finishes the training run. The last phase must be completed.
+func (s *TrainingRunSessionAdaptive) Finish(ctx context.Context) error {
+	if s.TrainingRunId == 0 {
+		return ErrTrainingRunNotAccessed
+	}
+	return s.Client.finishTrainingRun(ctx, "kypo-adaptive-training", s.TrainingRunId)
+}
+
+func trainingRunResourceName(service string) string {
+	if service == "kypo-adaptive-training" {
+		return "training run adaptive"
+	}
+	return "training run"
+}
+
+func (c *Client) accessTrainingRun(ctx context.Context, service, accessToken string) (*accessTrainingRunResponse, error) {
+	var response accessTrainingRunResponse
+	err := c.doJSONRequest(ctx, http.MethodPost, fmt.Sprintf("%s/%s/api/v1/training-runs?accessToken=%s", c.Endpoint, service,
+		url.QueryEscape(accessToken)), nil, &response, http.StatusOK, trainingRunResourceName(service), accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (c *Client) resumeTrainingRun(ctx context.Context, service string, runID int64) (*accessTrainingRunResponse, error) {
+	var response accessTrainingRunResponse
+	err := c.doJSONRequest(ctx, http.MethodGet, fmt.Sprintf("%s/%s/api/v1/training-runs/%d/resumption", c.Endpoint, service, runID),
+		nil, &response, http.StatusOK, trainingRunResourceName(service), runID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (c *Client) submitTrainingRunAnswer(ctx context.Context, service string, runID int64, answer string) (*AnswerResult, error) {
+	var result AnswerResult
+	err := c.doJSONRequestOnce(ctx, http.MethodPost, fmt.Sprintf("%s/%s/api/v1/training-runs/%d/is-correct-answer", c.Endpoint, service, runID),
+		struct {
+			Answer string `json:"answer"`
+		}{answer}, &result, http.StatusOK, trainingRunResourceName(service), runID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (c *Client) submitTrainingRunQuestions(ctx context.Context, service, path string, runID int64, answers []QuestionAnswer) error {
+	if answers == nil {
+		answers = []QuestionAnswer{}
+	}
+	return c.doJSONRequestOnce(ctx, http.MethodPost, fmt.Sprintf("%s/%s/api/v1/training-runs/%d/%s", c.Endpoint, service, runID, path),
+		answers, nil, http.StatusNoContent, trainingRunResourceName(service), runID)
+}
+
+func (c *Client) getTrainingRunSolution(ctx context.Context, service string, runID int64) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/%s/api/v1/training-runs/%d/solutions", c.Endpoint, service, runID), nil)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := c.doRequestOnce(req, http.StatusOK, trainingRunResourceName(service), runID)
+	if err != nil {
+		return "", err
+	}
+
+	return string(body), nil
+}
+
+func (c *Client) finishTrainingRun(ctx context.Context, service string, runID int64) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%s/%s/api/v1/training-runs/%d", c.Endpoint, service, runID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = c.doRequestWithRetry(req, http.StatusOK, trainingRunResourceName(service), runID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,283 @@
+package kypo_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const accessTrainingRunResponse = `{"training_run_id": 4, "show_stepper_bar": true, "sandbox_instance_ref_id": "6b1e2c3d",
+	"abstract_level_dto": {"id": 42, "title": "Scan the network", "level_type": "TRAINING_LEVEL", "order": 1, "max_score": 10,
+		"content": "Find the open port.", "hints": [{"id": 7, "title": "Tool", "hint_penalty": 2}]},
+	"info_about_levels": [{"id": 41, "title": "Introduction", "level_type": "INFO_LEVEL", "order": 0},
+		{"id": 42, "title": "Scan the network", "level_type": "TRAINING_LEVEL", "order": 1}]}`
+
+var expectedTrainingRunLevel = kypo.TrainingRunLevel{
+	Id:        42,
+	Title:     "Scan the network",
+	LevelType: kypo.LevelTypeTraining,
+	Order:     1,
+	MaxScore:  10,
+	Content:   "Find the open port.",
+	Hints:     []kypo.TrainingRunHint{{Id: 7, Title: "Tool", HintPenalty: 2}},
+}
+
+func TestTrainingRunSessionAccessSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+		assert.Equal(t, "/kypo-rest-training/api/v1/training-runs", request.URL.Path)
+		assert.Equal(t, http.MethodPost, request.Method)
+		assert.Equal(t, "class-1234", request.URL.Query().Get("accessToken"))
+
+		_, _ = fmt.Fprint(writer, accessTrainingRunResponse)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+	session := kypo.NewTrainingRunSession(&c)
+
+	actual, err := session.Access(context.Background(), "class-1234")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expectedTrainingRunLevel, actual)
+	assert.Equal(t, int64(4), session.TrainingRunId)
+	assert.Equal(t, []kypo.TrainingRunLevelSummary{
+		{Id: 41, Title: "Introduction", LevelType: kypo.LevelTypeInfo, Order: 0},
+		{Id: 42, Title: "Scan the network", LevelType: kypo.LevelTypeTraining, Order: 1},
+	}, session.Levels)
+}
+
+func TestTrainingRunSessionAccessNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+	session := kypo.NewTrainingRunSession(&c)
+
+	expected := &kypo.Error{
+		ResourceName: "training run",
+		Identifier:   "class-1234",
+		Err:          kypo.ErrNotFound,
+	}
+
+	actual, err := session.Access(context.Background(), "class-1234")
+
+	assert.Nil(t, actual)
+	assert.Equal(t, expected, err)
+	assert.Equal(t, int64(0), session.TrainingRunId)
+}
+
+func TestTrainingRunSessionNotAccessed(t *testing.T) {
+	session := kypo.NewTrainingRunSession(&kypo.Client{})
+
+	_, err := session.CurrentLevel(context.Background())
+	assert.Equal(t, kypo.ErrTrainingRunNotAccessed, err)
+
+	_, err = session.SubmitAnswer(context.Background(), "answer")
+	assert.Equal(t, kypo.ErrTrainingRunNotAccessed, err)
+
+	_, err = session.NextLevel(context.Background())
+	assert.Equal(t, kypo.ErrTrainingRunNotAccessed, err)
+
+	err = session.Finish(context.Background())
+	assert.Equal(t, kypo.ErrTrainingRunNotAccessed, err)
+}
+
+func TestTrainingRunSessionPlaythrough(t *testing.T) {
+	var requests []string
+	responses := map[string][]editResponse{
+		"GET /kypo-rest-training/api/v1/training-runs/4/resumption":         {{http.StatusOK, accessTrainingRunResponse}},
+		"GET /kypo-rest-training/api/v1/training-runs/4/hints/7":            {{http.StatusOK, `{"id": 7, "title": "Tool", "content": "Use nmap.", "hint_penalty": 2}`}},
+		"GET /kypo-rest-training/api/v1/training-runs/4/solutions":          {{http.StatusOK, "nmap -p- 10.1.0.5"}},
+		"POST /kypo-rest-training/api/v1/training-runs/4/is-correct-answer": {{http.StatusOK, `{"correct": false, "remaining_attempts": 2}`}, {http.StatusOK, `{"correct": true}`}},
+		"GET /kypo-rest-training/api/v1/training-runs/4/next-levels":        {{http.StatusOK, `{"id": 43, "title": "Done", "level_type": "INFO_LEVEL", "order": 2, "content": "Well done."}`}},
+		"PUT /kypo-rest-training/api/v1/training-runs/4":                    {{http.StatusOK, ""}},
+	}
+	bodies := map[string][]map[string]any{}
+	ts := editServer(t, responses, &requests, bodies)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+	session := kypo.NewTrainingRunSession(&c)
+	session.TrainingRunId = 4
+	ctx := context.Background()
+
+	level, err := session.CurrentLevel(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &expectedTrainingRunLevel, level)
+	assert.Len(t, session.Levels, 2)
+
+	hint, err := session.TakeHint(ctx, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, &kypo.TrainingRunHint{Id: 7, Title: "Tool", Content: "Use nmap.", HintPenalty: 2}, hint)
+
+	solution, err := session.TakeSolution(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "nmap -p- 10.1.0.5", solution)
+
+	result, err := session.SubmitAnswer(ctx, "21")
+	assert.NoError(t, err)
+	assert.Equal(t, &kypo.AnswerResult{Correct: false, RemainingAttempts: 2}, result)
+
+	result, err = session.SubmitAnswer(ctx, "22")
+	assert.NoError(t, err)
+	assert.True(t, result.Correct)
+
+	level, err = session.NextLevel(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &kypo.TrainingRunLevel{Id: 43, Title: "Done", LevelType: kypo.LevelTypeInfo, Order: 2, Content: "Well done."}, level)
+
+	assert.NoError(t, session.Finish(ctx))
+
+	assert.Equal(t, []map[string]any{{"answer": "21"}, {"answer": "22"}},
+		bodies["POST /kypo-rest-training/api/v1/training-runs/4/is-correct-answer"])
+}
+
+func TestTrainingRunSessionNextLevelNotCompleted(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusConflict)
+		_, _ = fmt.Fprint(writer, `{"message": "You need to answer the level to move to the next level."}`)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+	session := kypo.NewTrainingRunSession(&c)
+	session.TrainingRunId = 4
+
+	expected := &kypo.Error{
+		ResourceName: "training run",
+		Identifier:   int64(4),
+		Err:          fmt.Errorf(`status: 409, body: {"message": "You need to answer the level to move to the next level."}`),
+	}
+
+	actual, err := session.NextLevel(context.Background())
+
+	assert.Nil(t, actual)
+	assert.Equal(t, expected, err)
+}
+
+func TestTrainingRunSessionSubmitAssessment(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-rest-training/api/v1/training-runs/4/assessment-evaluations", request.URL.Path)
+		assert.Equal(t, http.MethodPost, request.Method)
+
+		body, _ := io.ReadAll(request.Body)
+		assert.JSONEq(t, `[{"question_id": 1, "answers": ["22"]},
+			{"question_id": 2, "answers": [], "extended_matching_pairs": [{"x": 0, "y": 1}]}]`, string(body))
+
+		writer.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+	session := kypo.NewTrainingRunSession(&c)
+	session.TrainingRunId = 4
+
+	err := session.SubmitAssessment(context.Background(), []kypo.QuestionAnswer{
+		{QuestionId: 1, Answers: []string{"22"}},
+		{QuestionId: 2, Answers: []string{}, ExtendedMatchingPairs: []kypo.ExtendedMatchingPair{{StatementOrder: 0, OptionOrder: 1}}},
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestTrainingRunSessionStateChangesNotRetried(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requests++
+		writer.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+	c.RetryCount = 2
+	session := kypo.NewTrainingRunSession(&c)
+	session.TrainingRunId = 4
+
+	_, err := session.SubmitAnswer(context.Background(), "22")
+	assert.Error(t, err)
+	assert.Equal(t, 1, requests)
+
+	err = session.SubmitAssessment(context.Background(), []kypo.QuestionAnswer{{QuestionId: 1, Answers: []string{"22"}}})
+	assert.Error(t, err)
+	assert.Equal(t, 2, requests)
+
+	_, err = session.TakeHint(context.Background(), 7)
+	assert.Error(t, err)
+	assert.Equal(t, 3, requests)
+
+	_, err = session.TakeSolution(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 4, requests)
+
+	_, err = session.NextLevel(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 5, requests)
+
+	adaptive := kypo.NewTrainingRunSessionAdaptive(&c)
+	adaptive.TrainingRunId = 5
+
+	_, err = adaptive.TakeSolution(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 6, requests)
+
+	_, err = adaptive.NextPhase(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 7, requests)
+}
+
+func TestTrainingRunSessionAdaptivePlaythrough(t *testing.T) {
+	var requests []string
+	responses := map[string][]editResponse{
+		"POST /kypo-adaptive-training/api/v1/training-runs": {{http.StatusOK, `{"training_run_id": 5,
+			"abstract_phase_dto": {"id": 11, "title": "Questionnaire", "phase_type": "QUESTIONNAIRE", "order": 0,
+				"questions": [{"id": 3, "question_type": "MCQ", "text": "Do you know nmap?", "order": 0,
+					"choices": [{"id": 8, "text": "Yes", "order": 0}, {"id": 9, "text": "No", "order": 1}]}]},
+			"info_about_phases": [{"id": 11, "title": "Questionnaire", "phase_type": "QUESTIONNAIRE", "order": 0},
+				{"id": 12, "title": "Scanning", "phase_type": "TRAINING", "order": 1}]}`}},
+		"POST /kypo-adaptive-training/api/v1/training-runs/5/questionnaire-evaluation": {{http.StatusNoContent, ""}},
+		"GET /kypo-adaptive-training/api/v1/training-runs/5/next-phases": {{http.StatusOK, `{"id": 12, "title": "Scanning",
+			"phase_type": "TRAINING", "order": 1, "task": {"id": 21, "title": "Easy scan", "content": "Scan 10.1.0.5.", "order": 0}}`}},
+		"POST /kypo-adaptive-training/api/v1/training-runs/5/is-correct-answer": {{http.StatusOK, `{"correct": true}`}},
+		"PUT /kypo-adaptive-training/api/v1/training-runs/5":                    {{http.StatusOK, ""}},
+	}
+	ts := editServer(t, responses, &requests, map[string][]map[string]any{})
+	defer ts.Close()
+
+	c := minimalClient(ts)
+	session := kypo.NewTrainingRunSessionAdaptive(&c)
+	ctx := context.Background()
+
+	phase, err := session.Access(ctx, "adaptive-1234")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), session.TrainingRunId)
+	assert.Equal(t, kypo.PhaseTypeQuestionnaire, phase.PhaseType)
+	assert.Equal(t, []kypo.TrainingRunChoice{{Id: 8, Text: "Yes", Order: 0}, {Id: 9, Text: "No", Order: 1}}, phase.Questions[0].Choices)
+	assert.Len(t, session.Phases, 2)
+
+	assert.NoError(t, session.SubmitQuestionnaire(ctx, []kypo.QuestionAnswer{{QuestionId: 3, Answers: []string{"Yes"}}}))
+
+	phase, err = session.NextPhase(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &kypo.TrainingRunTask{Id: 21, Title: "Easy scan", Content: "Scan 10.1.0.5.", Order: 0}, phase.Task)
+
+	result, err := session.SubmitAnswer(ctx, "22")
+	assert.NoError(t, err)
+	assert.True(t, result.Correct)
+
+	assert.NoError(t, session.Finish(ctx))
+	assert.Equal(t, []string{
+		"POST /kypo-adaptive-training/api/v1/training-runs",
+		"POST /kypo-adaptive-training/api/v1/training-runs/5/questionnaire-evaluation",
+		"GET /kypo-adaptive-training/api/v1/training-runs/5/next-phases",
+		"POST /kypo-adaptive-training/api/v1/training-runs/5/is-correct-answer",
+		"PUT /kypo-adaptive-training/api/v1/training-runs/5",
+	}, requests)
+}
@@ -0,0 +1,179 @@
+package kypo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// SolveReport is the outcome of SolveTraining.
+type SolveReport struct {
+	TrainingRunId int64
+	Levels        []SolveLevelResult
+}
+
+// SolveLevelResult is the outcome of playing a single level by SolveTraining.
+type SolveLevelResult struct {
+	Order     int64
+	Title     string
+	LevelType LevelType
+	// Err is nil if the level was completed. Otherwise, it wraps ErrAnswerRejected, ErrAnswerUnknown
+	// or ErrLevelNotReached.
+	Err error
+}
+
+// Failed returns the results of the levels which were not completed.
+func (r *SolveReport) Failed() []SolveLevelResult {
+	var failed []SolveLevelResult
+	for _, level := range r.Levels {
+		if level.Err != nil {
+			failed = append(failed, level)
+		}
+	}
+	return failed
+}
+
+// SolveTraining plays a new training run of the linear training instance with the given access token, using
+// the answers declared in the content of the definition of the instance. Training levels are answered with
+// their answer, access levels with their passkey and assessment levels with their correct choices.
+// Answers written as regular expressions between slashes are not submitted and are reported with ErrAnswerUnknown.
+// Hints are not taken.
+//
+// A level which does not accept its declared answer, or does not declare it, is reported in the SolveReport.
+// The solution of a failed training level is taken and the remaining levels are played. A failed access level
+// cannot be passed, so the following levels are reported with ErrLevelNotReached and the run is not finished.
+// Otherwise, the run is finished after the last level.
+// An error is returned if a request fails or the training run does not match the definition.
+func (c *Client) SolveTraining(ctx context.Context, definition *TrainingDefinition, accessToken string) (*SolveReport, error) {
+	spec, err := definition.Spec()
+	if err != nil {
+		return nil, err
+	}
+
+	levels := make(Levels, len(spec.Levels))
+	copy(levels, spec.Levels)
+	sort.SliceStable(levels, func(i, j int) bool {
+		return levels[i].Base().Order < levels[j].Base().Order
+	})
+
+	session := NewTrainingRunSession(c)
+	current, err := session.Access(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SolveReport{TrainingRunId: session.TrainingRunId}
+	for i, level := range levels {
+		base := level.Base()
+		if current.LevelType != base.LevelType {
+			return report, fmt.Errorf("level %d of training run %d is %s %q, but the definition declares %s %q",
+				i, session.TrainingRunId, current.LevelType, current.Title, base.LevelType, base.Title)
+		}
+
+		levelErr, err := solveLevel(ctx, session, level, current)
+		if err != nil {
+			return report, err
+		}
+		report.Levels = append(report.Levels, SolveLevelResult{
+			Order:     base.Order,
+			Title:     base.Title,
+			LevelType: base.LevelType,
+			Err:       levelErr,
+		})
+
+		if levelErr != nil && base.LevelType == LevelTypeTraining {
+			_, err = session.TakeSolution(ctx)
+			if err != nil {
+				return report, err
+			}
+		} else if levelErr != nil {
+			for _, notReached := range levels[i+1:] {
+				report.Levels = append(report.Levels, SolveLevelResult{
+					Order:     notReached.Base().Order,
+					Title:     notReached.Base().Title,
+					LevelType: notReached.Base().LevelType,
+					Err:       ErrLevelNotReached,
+				})
+			}
+			return report, nil
+		}
+
+		if i < len(levels)-1 {
+			current, err = session.NextLevel(ctx)
+			if err != nil {
+				return report, err
+			}
+		}
+	}
+
+	return report, session.Finish(ctx)
+}
+
+// solveLevel plays the current level of the session. It returns the failure of the level as levelErr
+// and the failure of a request as err.
+func solveLevel(ctx context.Context, session *TrainingRunSession, level Level, current *TrainingRunLevel) (levelErr error, err error) {
+	switch level := level.(type) {
+	case *TrainingLevel:
+		if level.Answer == "" {
+			if level.AnswerVariableName != "" {
+				return fmt.Errorf("%w: answer is given by the variable %q", ErrAnswerUnknown, level.AnswerVariableName), nil
+			}
+			return ErrAnswerUnknown, nil
+		}
+		if isRegexAnswer(level.Answer) {
+			return fmt.Errorf("%w: answer is the regular expression %s", ErrAnswerUnknown, level.Answer), nil
+		}
+		return submitDeclaredAnswer(ctx, session, level.Answer)
+	case *AccessLevel:
+		if level.Passkey == "" {
+			return ErrAnswerUnknown, nil
+		}
+		return submitDeclaredAnswer(ctx, session, level.Passkey)
+	case *AssessmentLevel:
+		return nil, session.SubmitAssessment(ctx, assessmentAnswers(level, current))
+	}
+	return nil, nil
+}
+
+func submitDeclaredAnswer(ctx context.Context, session *TrainingRunSession, answer string) (levelErr error, err error) {
+	result, err := session.SubmitAnswer(ctx, answer)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Correct {
+		return fmt.Errorf("%w: %q", ErrAnswerRejected, answer), nil
+	}
+	return nil, nil
+}
+
+// assessmentAnswers answers the questions of the current assessment level using the correct choices of the questions
+// of the definition level with the same order.
+func assessmentAnswers(level *AssessmentLevel, current *TrainingRunLevel) []QuestionAnswer {
+	questions := make(map[int64]AssessmentQuestion, len(level.Questions))
+	for _, question := range level.Questions {
+		questions[question.Order] = question
+	}
+
+	answers := make([]QuestionAnswer, 0, len(current.Questions))
+	for _, runQuestion := range current.Questions {
+		answer := QuestionAnswer{QuestionId: runQuestion.Id, Answers: []string{}}
+		question := questions[runQuestion.Order]
+		for _, choice := range question.Choices {
+			if choice.Correct {
+				answer.Answers = append(answer.Answers, choice.Text)
+			}
+		}
+		if question.QuestionType == "FFQ" && len(answer.Answers) > 1 {
+			// The choices of a free form question are the accepted alternatives of a single answer.
+			answer.Answers = answer.Answers[:1]
+		}
+		for _, statement := range question.ExtendedMatchingStatements {
+			answer.ExtendedMatchingPairs = append(answer.ExtendedMatchingPairs, ExtendedMatchingPair{
+				StatementOrder: statement.Order,
+				OptionOrder:    statement.CorrectOptionOrder,
+			})
+		}
+		answers = append(answers, answer)
+	}
+	return answers
+}
@@ -0,0 +1,251 @@
+package kypo_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const solverDefinition = `{"title": "Junior hacker", "levels": [
+	{"title": "Scan the network", "level_type": "TRAINING_LEVEL", "order": 2, "answer": "22"},
+	{"title": "Introduction", "level_type": "INFO_LEVEL", "order": 0, "content": "Welcome."},
+	{"title": "Get access", "level_type": "ACCESS_LEVEL", "order": 1, "passkey": "start"},
+	{"title": "Quiz", "level_type": "ASSESSMENT_LEVEL", "order": 3, "assessment_type": "TEST", "questions": [
+		{"question_type": "MCQ", "text": "Which port?", "order": 0, "choices": [
+			{"text": "21", "correct": false, "order": 0}, {"text": "22", "correct": true, "order": 1}]},
+		{"question_type": "FFQ", "text": "Which service?", "order": 1, "choices": [
+			{"text": "ssh", "correct": true, "order": 0}, {"text": "SSH", "correct": true, "order": 1}]},
+		{"question_type": "EMI", "text": "Match", "order": 2,
+			"extended_matching_options": [{"text": "ssh", "order": 0}, {"text": "ftp", "order": 1}],
+			"extended_matching_statements": [{"text": "22", "order": 0, "correct_option_order": 0},
+				{"text": "21", "order": 1, "correct_option_order": 1}]}]},
+	{"title": "Crack the password", "level_type": "TRAINING_LEVEL", "order": 4, "answer_variable_name": "password"}]}`
+
+// solverRun serves a linear training run with the levels of solverDefinition, which accepts the answers
+// in `accepted`, and records the submitted answers, assessments and the levels whose solution was taken.
+type solverRun struct {
+	t         *testing.T
+	levels    []string
+	accepted  map[string]bool
+	current   int
+	answers   []string
+	questions []map[string]any
+	solutions []int
+	finished  bool
+}
+
+func newSolverRun(t *testing.T, accepted ...string) *solverRun {
+	run := &solverRun{
+		t: t,
+		levels: []string{
+			`{"id": 1, "title": "Introduction", "level_type": "INFO_LEVEL", "order": 0, "content": "Welcome."}`,
+			`{"id": 2, "title": "Get access", "level_type": "ACCESS_LEVEL", "order": 1}`,
+			`{"id": 3, "title": "Scan the network", "level_type": "TRAINING_LEVEL", "order": 2}`,
+			`{"id": 4, "title": "Quiz", "level_type": "ASSESSMENT_LEVEL", "order": 3, "questions": [
+				{"id": 10, "question_type": "MCQ", "order": 0}, {"id": 11, "question_type": "FFQ", "order": 1},
+				{"id": 12, "question_type": "EMI", "order": 2}]}`,
+			`{"id": 5, "title": "Crack the password", "level_type": "TRAINING_LEVEL", "order": 4}`,
+		},
+		accepted: map[string]bool{},
+	}
+	for _, answer := range accepted {
+		run.accepted[answer] = true
+	}
+	return run
+}
+
+func (r *solverRun) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	assert.Equal(r.t, "Bearer token", request.Header.Get("Authorization"))
+	body, _ := io.ReadAll(request.Body)
+
+	switch request.Method + " " + request.URL.Path {
+	case "POST /kypo-rest-training/api/v1/training-runs":
+		assert.Equal(r.t, "class-1234", request.URL.Query().Get("accessToken"))
+		_, _ = fmt.Fprintf(writer, `{"training_run_id": 9, "abstract_level_dto": %s}`, r.levels[r.current])
+	case "POST /kypo-rest-training/api/v1/training-runs/9/is-correct-answer":
+		var answer struct {
+			Answer string `json:"answer"`
+		}
+		assert.NoError(r.t, json.Unmarshal(body, &answer))
+		r.answers = append(r.answers, answer.Answer)
+		_, _ = fmt.Fprintf(writer, `{"correct": %t, "remaining_attempts": 4}`, r.accepted[answer.Answer])
+	case "POST /kypo-rest-training/api/v1/training-runs/9/assessment-evaluations":
+		assert.NoError(r.t, json.Unmarshal(body, &r.questions))
+		writer.WriteHeader(http.StatusNoContent)
+	case "GET /kypo-rest-training/api/v1/training-runs/9/solutions":
+		r.solutions = append(r.solutions, r.current)
+		_, _ = fmt.Fprint(writer, "nmap")
+	case "GET /kypo-rest-training/api/v1/training-runs/9/next-levels":
+		r.current++
+		_, _ = fmt.Fprint(writer, r.levels[r.current])
+	case "PUT /kypo-rest-training/api/v1/training-runs/9":
+		r.finished = true
+	default:
+		r.t.Errorf("unexpected request %s %s", request.Method, request.URL.Path)
+		writer.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func TestSolveTrainingSuccessful(t *testing.T) {
+	run := newSolverRun(t, "start", "22")
+	run.levels = run.levels[:4]
+	ts := httptest.NewServer(run)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	var spec map[string]any
+	require.NoError(t, json.Unmarshal([]byte(solverDefinition), &spec))
+	spec["levels"] = spec["levels"].([]any)[:4]
+	content, _ := json.Marshal(spec)
+
+	report, err := c.SolveTraining(context.Background(), &kypo.TrainingDefinition{Id: 1, Content: string(content)}, "class-1234")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &kypo.SolveReport{
+		TrainingRunId: 9,
+		Levels: []kypo.SolveLevelResult{
+			{Order: 0, Title: "Introduction", LevelType: kypo.LevelTypeInfo},
+			{Order: 1, Title: "Get access", LevelType: kypo.LevelTypeAccess},
+			{Order: 2, Title: "Scan the network", LevelType: kypo.LevelTypeTraining},
+			{Order: 3, Title: "Quiz", LevelType: kypo.LevelTypeAssessment},
+		},
+	}, report)
+	assert.Empty(t, report.Failed())
+	assert.Equal(t, []string{"start", "22"}, run.answers)
+	assert.Equal(t, []map[string]any{
+		{"question_id": float64(10), "answers": []any{"22"}},
+		{"question_id": float64(11), "answers": []any{"ssh"}},
+		{"question_id": float64(12), "answers": []any{}, "extended_matching_pairs": []any{
+			map[string]any{"x": float64(0), "y": float64(0)},
+			map[string]any{"x": float64(1), "y": float64(1)},
+		}},
+	}, run.questions)
+	assert.True(t, run.finished)
+}
+
+func TestSolveTrainingAnswerRejected(t *testing.T) {
+	run := newSolverRun(t, "start")
+	ts := httptest.NewServer(run)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	report, err := c.SolveTraining(context.Background(), &kypo.TrainingDefinition{Id: 1, Content: solverDefinition}, "class-1234")
+
+	assert.NoError(t, err)
+	failed := report.Failed()
+	require.Len(t, failed, 2)
+	assert.Equal(t, "Scan the network", failed[0].Title)
+	assert.ErrorIs(t, failed[0].Err, kypo.ErrAnswerRejected)
+	assert.EqualError(t, failed[0].Err, `declared answer was rejected: "22"`)
+	assert.Equal(t, "Crack the password", failed[1].Title)
+	assert.ErrorIs(t, failed[1].Err, kypo.ErrAnswerUnknown)
+	assert.Len(t, report.Levels, 5)
+	assert.Equal(t, []int{2, 4}, run.solutions)
+	assert.NotNil(t, run.questions)
+	assert.True(t, run.finished)
+}
+
+func TestSolveTrainingPasskeyRejected(t *testing.T) {
+	run := newSolverRun(t)
+	ts := httptest.NewServer(run)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	report, err := c.SolveTraining(context.Background(), &kypo.TrainingDefinition{Id: 1, Content: solverDefinition}, "class-1234")
+
+	assert.NoError(t, err)
+	failed := report.Failed()
+	require.Len(t, failed, 4)
+	assert.Equal(t, "Get access", failed[0].Title)
+	assert.ErrorIs(t, failed[0].Err, kypo.ErrAnswerRejected)
+	for _, notReached := range failed[1:] {
+		assert.Equal(t, kypo.ErrLevelNotReached, notReached.Err)
+	}
+	assert.Empty(t, run.solutions)
+	assert.False(t, run.finished)
+}
+
+func TestSolveTrainingVariableAnswer(t *testing.T) {
+	run := newSolverRun(t, "start", "22")
+	ts := httptest.NewServer(run)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	report, err := c.SolveTraining(context.Background(), &kypo.TrainingDefinition{Id: 1, Content: solverDefinition}, "class-1234")
+
+	assert.NoError(t, err)
+	failed := report.Failed()
+	require.Len(t, failed, 1)
+	assert.Equal(t, int64(4), failed[0].Order)
+	assert.ErrorIs(t, failed[0].Err, kypo.ErrAnswerUnknown)
+	assert.EqualError(t, failed[0].Err, `answer is not declared in the training definition: answer is given by the variable "password"`)
+	assert.Equal(t, []int{4}, run.solutions)
+	assert.True(t, run.finished)
+}
+
+func TestSolveTrainingRegexAnswer(t *testing.T) {
+	run := newSolverRun(t, "start", "22")
+	ts := httptest.NewServer(run)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	var spec map[string]any
+	require.NoError(t, json.Unmarshal([]byte(solverDefinition), &spec))
+	levels := spec["levels"].([]any)
+	levels[0].(map[string]any)["answer"] = "/^2{2}$/"
+	spec["levels"] = levels[:4]
+	content, _ := json.Marshal(spec)
+	run.levels = run.levels[:4]
+
+	report, err := c.SolveTraining(context.Background(), &kypo.TrainingDefinition{Id: 1, Content: string(content)}, "class-1234")
+
+	assert.NoError(t, err)
+	failed := report.Failed()
+	require.Len(t, failed, 1)
+	assert.Equal(t, "Scan the network", failed[0].Title)
+	assert.ErrorIs(t, failed[0].Err, kypo.ErrAnswerUnknown)
+	assert.EqualError(t, failed[0].Err, "answer is not declared in the training definition: answer is the regular expression /^2{2}$/")
+	assert.Equal(t, []string{"start"}, run.answers)
+	assert.Equal(t, []int{2}, run.solutions)
+	assert.True(t, run.finished)
+}
+
+func TestSolveTrainingLevelMismatch(t *testing.T) {
+	run := newSolverRun(t)
+	run.levels[0] = `{"id": 1, "title": "Scan", "level_type": "TRAINING_LEVEL", "order": 0}`
+	ts := httptest.NewServer(run)
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	report, err := c.SolveTraining(context.Background(), &kypo.TrainingDefinition{Id: 1, Content: solverDefinition}, "class-1234")
+
+	assert.EqualError(t, err, `level 0 of training run 9 is TRAINING_LEVEL "Scan", but the definition declares INFO_LEVEL "Introduction"`)
+	assert.Empty(t, report.Levels)
+}
+
+func TestSolveTrainingAccessNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	report, err := c.SolveTraining(context.Background(), &kypo.TrainingDefinition{Id: 1, Content: solverDefinition}, "class-1234")
+
+	assert.Nil(t, report)
+	assert.ErrorIs(t, err, kypo.ErrNotFound)
+}
@@ -0,0 +1,200 @@
+package kypo_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var (
+	trainingRunResponse = `{"id": 4, "instance_id": 1, "definition_id": 3, "state": "RUNNING",
+		"start_time": "2024-02-01T09:05:00Z", "end_time": "2024-02-01T12:00:00Z", "event_log_reference": null,
+		"sandbox_instance_ref_id": "6b1e2c3d", "sandbox_instance_allocation_id": 12,
+		"participant_ref": {"user_ref_id": 7, "sub": "student", "full_name": "Student", "given_name": "Stu", "family_name": "Dent"},
+		"current_level": {"id": 42, "title": "Scan the network", "level_type": "TRAINING_LEVEL"}}`
+
+	expectedTrainingRun = kypo.TrainingRun{
+		Id:                      4,
+		TrainingInstanceId:      1,
+		State:                   kypo.TrainingRunStateRunning,
+		StartTime:               time.Date(2024, 2, 1, 9, 5, 0, 0, time.UTC),
+		EndTime:                 time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC),
+		Participant:             kypo.User{Id: 7, Sub: "student", FullName: "Student", GivenName: "Stu", FamilyName: "Dent"},
+		CurrentLevelId:          42,
+		SandboxId:               "6b1e2c3d",
+		SandboxAllocationUnitId: 12,
+	}
+)
+
+func TestListTrainingRunsSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+		assert.Equal(t, "/kypo-rest-training/api/v1/training-instances/1/training-runs", request.URL.Path)
+		assert.Equal(t, http.MethodGet, request.Method)
+
+		_, _ = fmt.Fprintf(writer, `{"content": [%s], "pagination": {"number": 0, "number_of_elements": 1, "size": 100,
+			"total_elements": 1, "total_pages": 1}}`, trainingRunResponse)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.ListTrainingRuns(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []kypo.TrainingRun{expectedTrainingRun}, actual)
+}
+
+func TestListTrainingRunsNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.Error{
+		ResourceName: "training runs",
+		Identifier:   "training instance 1",
+		Err:          kypo.ErrNotFound,
+	}
+
+	actual, err := c.ListTrainingRuns(context.Background(), 1)
+
+	assert.Nil(t, actual)
+	assert.Equal(t, expected, err)
+}
+
+func TestGetTrainingRunSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-rest-training/api/v1/training-runs/4", request.URL.Path)
+		assert.Equal(t, http.MethodGet, request.Method)
+
+		_, _ = fmt.Fprint(writer, trainingRunResponse)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.GetTrainingRun(context.Background(), 4)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expectedTrainingRun, actual)
+}
+
+func TestDeleteTrainingRunSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-rest-training/api/v1/training-runs/4", request.URL.Path)
+		assert.Equal(t, http.MethodDelete, request.Method)
+		assert.Equal(t, "true", request.URL.Query().Get("forceDelete"))
+
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	err := c.DeleteTrainingRun(context.Background(), 4, true)
+
+	assert.NoError(t, err)
+}
+
+func TestDeleteTrainingRunServerError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "false", request.URL.Query().Get("forceDelete"))
+
+		writer.WriteHeader(http.StatusConflict)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := &kypo.Error{
+		ResourceName: "training run",
+		Identifier:   int64(4),
+		Err:          fmt.Errorf("status: 409, body: "),
+	}
+
+	err := c.DeleteTrainingRun(context.Background(), 4, false)
+
+	assert.Equal(t, expected, err)
+}
+
+func TestArchiveTrainingRunSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-rest-training/api/v1/training-runs/4/archive", request.URL.Path)
+		assert.Equal(t, http.MethodPatch, request.Method)
+
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	err := c.ArchiveTrainingRun(context.Background(), 4)
+
+	assert.NoError(t, err)
+}
+
+func TestGetTrainingRunAdaptiveSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-adaptive-training/api/v1/training-runs/4", request.URL.Path)
+
+		_, _ = fmt.Fprint(writer, `{"id": 4, "instance_id": 1, "state": "FINISHED", "current_phase": {"id": 43},
+			"participant_ref": {"user_ref_id": 7, "sub": "student"}}`)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	expected := kypo.TrainingRun{
+		Id:                 4,
+		TrainingInstanceId: 1,
+		State:              kypo.TrainingRunStateFinished,
+		Participant:        kypo.User{Id: 7, Sub: "student"},
+		CurrentLevelId:     43,
+	}
+
+	actual, err := c.GetTrainingRunAdaptive(context.Background(), 4)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expected, actual)
+}
+
+func TestListTrainingRunsAdaptiveSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-adaptive-training/api/v1/training-instances/1/training-runs", request.URL.Path)
+
+		_, _ = fmt.Fprint(writer, `{"content": [{"id": 4, "state": "RUNNING"}], "pagination": {"number": 0,
+			"number_of_elements": 1, "size": 100, "total_elements": 1, "total_pages": 1}}`)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	actual, err := c.ListTrainingRunsAdaptive(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []kypo.TrainingRun{{Id: 4, TrainingInstanceId: 1, State: kypo.TrainingRunStateRunning}}, actual)
+}
+
+func TestDeleteTrainingRunAdaptiveSuccessful(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/kypo-adaptive-training/api/v1/training-runs/4", request.URL.Path)
+		assert.Equal(t, http.MethodDelete, request.Method)
+
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	c := minimalClient(ts)
+
+	err := c.DeleteTrainingRunAdaptive(context.Background(), 4, true)
+
+	assert.NoError(t, err)
+}
@@ -0,0 +1,220 @@
+package kypo
+
+import (
+	"context"
+	"io"
+)
+
+// TrainingType is the type of training, which determines the training service used for its instances and runs.
+type TrainingType string
+
+const (
+	TrainingTypeLinear   TrainingType = "LINEAR"
+	TrainingTypeAdaptive TrainingType = "ADAPTIVE"
+)
+
+// TrainingService manages the training instances and runs of a single training type. It is implemented
+// by LinearTrainingService and AdaptiveTrainingService, so the same code can manage both types of trainings.
+type TrainingService interface {
+	CreateTrainingInstance(ctx context.Context, request TrainingInstanceRequest) (*TrainingInstance, error)
+	GetTrainingInstance(ctx context.Context, instanceID int64) (*TrainingInstance, error)
+	UpdateTrainingInstance(ctx context.Context, instanceID int64, request TrainingInstanceRequest) (*TrainingInstance, error)
+	DeleteTrainingInstance(ctx context.Context, instanceID int64, force bool) error
+	ListTrainingInstances(ctx context.Context) ([]TrainingInstance, error)
+	AddTrainingInstanceOrganizers(ctx context.Context, instanceID int64, userIDs ...int64) error
+	RemoveTrainingInstanceOrganizers(ctx context.Context, instanceID int64, userIDs ...int64) error
+
+	AssignPoolToTrainingInstance(ctx context.Context, instanceID, poolID int64) (*SandboxPool, error)
+	UnassignPoolFromTrainingInstance(ctx context.Context, instanceID int64) (*SandboxPool, error)
+
+	GetTrainingInstanceAccessToken(ctx context.Context, instanceID int64) (string, error)
+	RegenerateTrainingInstanceAccessToken(ctx context.Context, instanceID int64, prefix string) (string, error)
+	ValidateTrainingInstanceAccessToken(ctx context.Context, accessToken string) (*TrainingInstance, error)
+	IsAccessTokenAvailable(ctx context.Context, prefix string) (bool, error)
+
+	GetTrainingInstanceResults(ctx context.Context, instanceID int64) (*TrainingInstanceResults, error)
+	ExportTrainingInstanceArchive(ctx context.Context, instanceID int64, w io.Writer) (int64, error)
+
+	ListTrainingRuns(ctx context.Context, instanceID int64) ([]TrainingRun, error)
+	GetTrainingRun(ctx context.Context, runID int64) (*TrainingRun, error)
+	DeleteTrainingRun(ctx context.Context, runID int64, force bool) error
+	ArchiveTrainingRun(ctx context.Context, runID int64) error
+}
+
+// LinearTrainingService is the TrainingService of linear trainings.
+type LinearTrainingService struct {
+	Client *Client
+}
+
+// AdaptiveTrainingService is the TrainingService of adaptive trainings.
+type AdaptiveTrainingService struct {
+	Client *Client
+}
+
+var (
+	_ TrainingService = LinearTrainingService{}
+	_ TrainingService = AdaptiveTrainingService{}
+)
+
+// TrainingService returns the TrainingService of the training type. It returns nil for unknown training types.
+func (c *Client) TrainingService(trainingType TrainingType) TrainingService {
+	switch trainingType {
+	case TrainingTypeLinear:
+		return LinearTrainingService{Client: c}
+	case TrainingTypeAdaptive:
+		return AdaptiveTrainingService{Client: c}
+	}
+	return nil
+}
+
+func (s LinearTrainingService) CreateTrainingInstance(ctx context.Context, request TrainingInstanceRequest) (*TrainingInstance, error) {
+	return s.Client.CreateTrainingInstance(ctx, request)
+}
+
+func (s LinearTrainingService) GetTrainingInstance(ctx context.Context, instanceID int64) (*TrainingInstance, error) {
+	return s.Client.GetTrainingInstance(ctx, instanceID)
+}
+
+func (s LinearTrainingService) UpdateTrainingInstance(ctx context.Context, instanceID int64, request TrainingInstanceRequest) (*TrainingInstance, error) {
+	return s.Client.UpdateTrainingInstance(ctx, instanceID, request)
+}
+
+func (s LinearTrainingService) DeleteTrainingInstance(ctx context.Context, instanceID int64, force bool) error {
+	return s.Client.DeleteTrainingInstance(ctx, instanceID, force)
+}
+
+func (s LinearTrainingService) ListTrainingInstances(ctx context.Context) ([]TrainingInstance, error) {
+	return s.Client.ListTrainingInstances(ctx)
+}
+
+func (s LinearTrainingService) AddTrainingInstanceOrganizers(ctx context.Context, instanceID int64, userIDs ...int64) error {
+	return s.Client.AddTrainingInstanceOrganizers(ctx, instanceID, userIDs...)
+}
+
+func (s LinearTrainingService) RemoveTrainingInstanceOrganizers(ctx context.Context, instanceID int64, userIDs ...int64) error {
+	return s.Client.RemoveTrainingInstanceOrganizers(ctx, instanceID, userIDs...)
+}
+
+func (s LinearTrainingService) AssignPoolToTrainingInstance(ctx context.Context, instanceID, poolID int64) (*SandboxPool, error) {
+	return s.Client.AssignPoolToTrainingInstance(ctx, instanceID, poolID)
+}
+
+func (s LinearTrainingService) UnassignPoolFromTrainingInstance(ctx context.Context, instanceID int64) (*SandboxPool, error) {
+	return s.Client.UnassignPoolFromTrainingInstance(ctx, instanceID)
+}
+
+func (s LinearTrainingService) GetTrainingInstanceAccessToken(ctx context.Context, instanceID int64) (string, error) {
+	return s.Client.GetTrainingInstanceAccessToken(ctx, instanceID)
+}
+
+func (s LinearTrainingService) RegenerateTrainingInstanceAccessToken(ctx context.Context, instanceID int64, prefix string) (string, error) {
+	return s.Client.RegenerateTrainingInstanceAccessToken(ctx, instanceID, prefix)
+}
+
+func (s LinearTrainingService) ValidateTrainingInstanceAccessToken(ctx context.Context, accessToken string) (*TrainingInstance, error) {
+	return s.Client.ValidateTrainingInstanceAccessToken(ctx, accessToken)
+}
+
+func (s LinearTrainingService) IsAccessTokenAvailable(ctx context.Context, prefix string) (bool, error) {
+	return s.Client.IsAccessTokenAvailable(ctx, prefix)
+}
+
+func (s LinearTrainingService) GetTrainingInstanceResults(ctx context.Context, instanceID int64) (*TrainingInstanceResults, error) {
+	return s.Client.GetTrainingInstanceResults(ctx, instanceID)
+}
+
+func (s LinearTrainingService) ExportTrainingInstanceArchive(ctx context.Context, instanceID int64, w io.Writer) (int64, error) {
+	return s.Client.ExportTrainingInstanceArchive(ctx, instanceID, w)
+}
+
+func (s LinearTrainingService) ListTrainingRuns(ctx context.Context, instanceID int64) ([]TrainingRun, error) {
+	return s.Client.ListTrainingRuns(ctx, instanceID)
+}
+
+func (s LinearTrainingService) GetTrainingRun(ctx context.Context, runID int64) (*TrainingRun, error) {
+	return s.Client.GetTrainingRun(ctx, runID)
+}
+
+func (s LinearTrainingService) DeleteTrainingRun(ctx context.Context, runID int64, force bool) error {
+	return s.Client.DeleteTrainingRun(ctx, runID, force)
+}
+
+func (s LinearTrainingService) ArchiveTrainingRun(ctx context.Context, runID int64) error {
+	return s.Client.ArchiveTrainingRun(ctx, runID)
+}
+
+func (s AdaptiveTrainingService) CreateTrainingInstance(ctx context.Context, request TrainingInstanceRequest) (*TrainingInstance, error) {
+	return s.Client.CreateTrainingInstanceAdaptive(ctx, request)
+}
+
+func (s AdaptiveTrainingService) GetTrainingInstance(ctx context.Context, instanceID int64) (*TrainingInstance, error) {
+	return s.Client.GetTrainingInstanceAdaptive(ctx, instanceID)
+}
+
+func (s AdaptiveTrainingService) UpdateTrainingInstance(ctx context.Context, instanceID int64, request TrainingInstanceRequest) (*TrainingInstance, error) {
+	return s.Client.UpdateTrainingInstanceAdaptive(ctx, instanceID, request)
+}
+
+func (s AdaptiveTrainingService) DeleteTrainingInstance(ctx context.Context, instanceID int64, force bool) error {
+	return s.Client.DeleteTrainingInstanceAdaptive(ctx, instanceID, force)
+}
+
+func (s AdaptiveTrainingService) ListTrainingInstances(ctx context.Context) ([]TrainingInstance, error) {
+	return s.Client.ListTrainingInstancesAdaptive(ctx)
+}
+
+func (s AdaptiveTrainingService) AddTrainingInstanceOrganizers(ctx context.Context, instanceID int64, userIDs ...int64) error {
+	return s.Client.AddTrainingInstanceAdaptiveOrganizers(ctx, instanceID, userIDs...)
+}
+
+func (s AdaptiveTrainingService) RemoveTrainingInstanceOrganizers(ctx context.Context, instanceID int64, userIDs ...int64) error {
+	return s.Client.RemoveTrainingInstanceAdaptiveOrganizers(ctx, instanceID, userIDs...)
+}
+
+func (s AdaptiveTrainingService) AssignPoolToTrainingInstance(ctx context.Context, instanceID, poolID int64) (*SandboxPool, error) {
+	return s.Client.AssignPoolToTrainingInstanceAdaptive(ctx, instanceID, poolID)
+}
+
+func (s AdaptiveTrainingService) UnassignPoolFromTrainingInstance(ctx context.Context, instanceID int64) (*SandboxPool, error) {
+	return s.Client.UnassignPoolFromTrainingInstanceAdaptive(ctx, instanceID)
+}
+
+func (s AdaptiveTrainingService) GetTrainingInstanceAccessToken(ctx context.Context, instanceID int64) (string, error) {
+	return s.Client.GetTrainingInstanceAdaptiveAccessToken(ctx, instanceID)
+}
+
+func (s AdaptiveTrainingService) RegenerateTrainingInstanceAccessToken(ctx context.Context, instanceID int64, prefix string) (string, error) {
+	return s.Client.RegenerateTrainingInstanceAdaptiveAccessToken(ctx, instanceID, prefix)
+}
+
+func (s AdaptiveTrainingService) ValidateTrainingInstanceAccessToken(ctx context.Context, accessToken string) (*TrainingInstance, error) {
+	return s.Client.ValidateTrainingInstanceAdaptiveAccessToken(ctx, accessToken)
+}
+
+func (s AdaptiveTrainingService) IsAccessTokenAvailable(ctx context.Context, prefix string) (bool, error) {
+	return s.Client.IsAccessTokenAvailableAdaptive(ctx, prefix)
+}
+
+func (s AdaptiveTrainingService) GetTrainingInstanceResults(ctx context.Context, instanceID int64) (*TrainingInstanceResults, error) {
+	return s.Client.GetTrainingInstanceResultsAdaptive(ctx, instanceID)
+}
+
+func (s AdaptiveTrainingService) ExportTrainingInstanceArchive(ctx context.Context, instanceID int64, w io.Writer) (int64, error) {
+	return s.Client.ExportTrainingInstanceArchiveAdaptive(ctx, instanceID, w)
+}
+
+func (s AdaptiveTrainingService) ListTrainingRuns(ctx context.Context, instanceID int64) ([]TrainingRun, error) {
+	return s.Client.ListTrainingRunsAdaptive(ctx, instanceID)
+}
+
+func (s AdaptiveTrainingService) GetTrainingRun(ctx context.Context, runID int64) (*TrainingRun, error) {
+	return s.Client.GetTrainingRunAdaptive(ctx, runID)
+}
+
+func (s AdaptiveTrainingService) DeleteTrainingRun(ctx context.Context, runID int64, force bool) error {
+	return s.Client.DeleteTrainingRunAdaptive(ctx, runID, force)
+}
+
+func (s AdaptiveTrainingService) ArchiveTrainingRun(ctx context.Context, runID int64) error {
+	return s.Client.ArchiveTrainingRunAdaptive(ctx, runID)
+}
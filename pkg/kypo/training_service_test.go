@@ -0,0 +1,74 @@
+package kypo_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/vydrazde/kypo-go-client/pkg/kypo"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTrainingServiceUnknownType(t *testing.T) {
+	c := kypo.Client{}
+
+	assert.Nil(t, c.TrainingService("UNKNOWN"))
+}
+
+func TestTrainingService(t *testing.T) {
+	tests := []struct {
+		trainingType kypo.TrainingType
+		service      kypo.TrainingService
+		path         string
+	}{
+		{kypo.TrainingTypeLinear, kypo.LinearTrainingService{}, "/kypo-rest-training/api/v1"},
+		{kypo.TrainingTypeAdaptive, kypo.AdaptiveTrainingService{}, "/kypo-adaptive-training/api/v1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.trainingType), func(t *testing.T) {
+			var requests []string
+			ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+				requests = append(requests, request.Method+" "+request.URL.Path)
+
+				switch request.URL.Path {
+				case tt.path + "/training-instances/1":
+					_, _ = fmt.Fprint(writer, trainingInstanceResponse)
+				case tt.path + "/training-instances/1/training-runs":
+					_, _ = fmt.Fprintf(writer, `{"content": [%s], "pagination": {"number": 0, "number_of_elements": 1,
+						"size": 100, "total_elements": 1, "total_pages": 1}}`, trainingRunResponse)
+				case tt.path + "/visualizations/training-instances/1/table":
+					_, _ = fmt.Fprint(writer, `[]`)
+				}
+			}))
+			defer ts.Close()
+
+			c := minimalClient(ts)
+			service := c.TrainingService(tt.trainingType)
+			assert.IsType(t, tt.service, service)
+
+			instance, err := service.GetTrainingInstance(context.Background(), 1)
+			assert.NoError(t, err)
+			assert.Equal(t, &expectedTrainingInstance, instance)
+
+			runs, err := service.ListTrainingRuns(context.Background(), 1)
+			assert.NoError(t, err)
+			assert.Equal(t, []kypo.TrainingRun{expectedTrainingRun}, runs)
+
+			results, err := service.GetTrainingInstanceResults(context.Background(), 1)
+			assert.NoError(t, err)
+			assert.Equal(t, &kypo.TrainingInstanceResults{TrainingInstanceId: 1, Participants: []kypo.ParticipantResult{}}, results)
+
+			assert.NoError(t, service.DeleteTrainingRun(context.Background(), 4, true))
+
+			assert.Equal(t, []string{
+				http.MethodGet + " " + tt.path + "/training-instances/1",
+				http.MethodGet + " " + tt.path + "/training-instances/1/training-runs",
+				http.MethodGet + " " + tt.path + "/visualizations/training-instances/1/table",
+				http.MethodDelete + " " + tt.path + "/training-runs/4",
+			}, requests)
+		})
+	}
+}
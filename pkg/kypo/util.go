@@ -1,9 +1,13 @@
 package kypo
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -36,11 +40,20 @@ func (c *Client) doRequest(req *http.Request) (body []byte, statusCode int, err
 	return
 }
 
-func (c *Client) doRequestWithRetry(req *http.Request, expectedStatusCode int, resourceName string, identifier any) (body []byte, statusCode int, err error) {
+// doRequestWithRetry sends the request until the response has `expectedStatusCode`, at most RetryCount times more.
+// Responses with one of `finalStatusCodes` are not retried, because repeating the request would not change them.
+func (c *Client) doRequestWithRetry(req *http.Request, expectedStatusCode int, resourceName string, identifier any, finalStatusCodes ...int) (body []byte, statusCode int, err error) {
 	duration := 50 * time.Millisecond
 	timer := time.NewTimer(duration)
 	defer timer.Stop()
 	for i := 0; i <= c.RetryCount; i++ {
+		if i > 0 && req.GetBody != nil {
+			// The body of the previous attempt was consumed
+			req.Body, err = req.GetBody()
+			if err != nil {
+				return
+			}
+		}
 		body, statusCode, err = c.doRequest(req)
 		if err != nil {
 			return
@@ -53,6 +66,11 @@ func (c *Client) doRequestWithRetry(req *http.Request, expectedStatusCode int, r
 		default:
 			err = &Error{ResourceName: resourceName, Identifier: identifier, Err: fmt.Errorf("status: %d, body: %s", statusCode, body)}
 		}
+		for _, finalStatusCode := range finalStatusCodes {
+			if statusCode == finalStatusCode {
+				return
+			}
+		}
 		timer.Stop()
 		duration *= 2
 		timer.Reset(duration)
@@ -68,9 +86,292 @@ func (c *Client) doRequestWithRetry(req *http.Request, expectedStatusCode int, r
 	return
 }
 
+// doJSONRequest sends a request with `requestBody` encoded as JSON, unless it is nil, and decodes the response body
+// into `response`, unless it is nil. The request is retried like in doRequestWithRetry.
+func (c *Client) doJSONRequest(ctx context.Context, method, url string, requestBody, response any, expectedStatusCode int, resourceName string, identifier any) error {
+	req, err := newJSONRequest(ctx, method, url, requestBody)
+	if err != nil {
+		return err
+	}
+
+	body, _, err := c.doRequestWithRetry(req, expectedStatusCode, resourceName, identifier)
+	if err != nil {
+		return err
+	}
+
+	return unmarshalJSONResponse(body, response)
+}
+
+// doJSONRequestOnce sends a request like doJSONRequest, but it is never retried. It is used for requests which
+// must not be repeated, because the server records each of them, e.g. submitted answers.
+func (c *Client) doJSONRequestOnce(ctx context.Context, method, url string, requestBody, response any, expectedStatusCode int, resourceName string, identifier any) error {
+	req, err := newJSONRequest(ctx, method, url, requestBody)
+	if err != nil {
+		return err
+	}
+
+	body, err := c.doRequestOnce(req, expectedStatusCode, resourceName, identifier)
+	if err != nil {
+		return err
+	}
+
+	return unmarshalJSONResponse(body, response)
+}
+
+// doRequestOnce sends the request once and returns the body of the response with `expectedStatusCode`.
+// Other responses are turned into errors like in doRequestWithRetry.
+func (c *Client) doRequestOnce(req *http.Request, expectedStatusCode int, resourceName string, identifier any) ([]byte, error) {
+	body, statusCode, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	switch statusCode {
+	case expectedStatusCode:
+		return body, nil
+	case http.StatusNotFound:
+		return nil, &Error{ResourceName: resourceName, Identifier: identifier, Err: ErrNotFound}
+	default:
+		return nil, &Error{ResourceName: resourceName, Identifier: identifier, Err: fmt.Errorf("status: %d, body: %s", statusCode, body)}
+	}
+}
+
+func newJSONRequest(ctx context.Context, method, url string, requestBody any) (*http.Request, error) {
+	var reader io.Reader
+	if requestBody != nil {
+		encoded, err := marshalJSON(requestBody)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	return http.NewRequestWithContext(ctx, method, url, reader)
+}
+
+func unmarshalJSONResponse(body []byte, response any) error {
+	if response == nil {
+		return nil
+	}
+	return json.Unmarshal(body, response)
+}
+
+// doStreamRequest copies the body of the response with `expectedStatusCode` to `w` without buffering it.
+// Responses with other status codes are retried like in doRequestWithRetry, nothing is written to `w` for them.
+func (c *Client) doStreamRequest(req *http.Request, w io.Writer, expectedStatusCode int, resourceName string, identifier any) (written int64, err error) {
+	duration := 50 * time.Millisecond
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	for i := 0; i <= c.RetryCount; i++ {
+		err = c.refreshToken(req.Context())
+		if err != nil {
+			return
+		}
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+
+		var res *http.Response
+		res, err = c.HTTPClient.Do(req)
+		if err != nil {
+			return
+		}
+		if res.StatusCode == expectedStatusCode {
+			written, err = io.Copy(w, res.Body)
+			err2 := res.Body.Close()
+			if err == nil {
+				err = err2
+			}
+			return
+		}
+
+		body, _ := io.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if res.StatusCode == http.StatusNotFound {
+			err = &Error{ResourceName: resourceName, Identifier: identifier, Err: ErrNotFound}
+		} else {
+			err = &Error{ResourceName: resourceName, Identifier: identifier, Err: fmt.Errorf("status: %d, body: %s", res.StatusCode, body)}
+		}
+		timer.Stop()
+		duration *= 2
+		timer.Reset(duration)
+		select {
+		case <-req.Context().Done():
+			err = req.Context().Err()
+			return
+		case <-timer.C:
+		}
+	}
+	return
+}
+
 func boolToString(b bool) string {
 	if b {
 		return "true"
 	}
 	return "false"
 }
+
+// marshalJSON encodes `v` like json.Marshal, but does not escape HTML characters,
+// which are common in the markdown content of training definitions.
+func marshalJSON(v any) ([]byte, error) {
+	buffer := bytes.Buffer{}
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
+
+// unmarshalObject decodes the JSON object `data` into `v` and returns all fields of the object,
+// so that fields unknown to `v` can be preserved by marshalObject.
+// The `v` must not implement json.Unmarshaler, otherwise the call would recurse.
+func unmarshalObject(data []byte, v any) (map[string]json.RawMessage, error) {
+	err := json.Unmarshal(data, v)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// marshalObject encodes `v` as a JSON object merged with the `original` fields returned by unmarshalObject.
+// Fields unknown to `v` are kept. Fields of `v` with a zero value are kept null or left out
+// if they were null or missing in the original object, so that unmodified objects are encoded losslessly.
+// The `v` must not implement json.Marshaler, otherwise the call would recurse.
+func marshalObject(v any, original map[string]json.RawMessage) ([]byte, error) {
+	encoded, err := marshalJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	if original == nil {
+		return encoded, nil
+	}
+
+	known := map[string]json.RawMessage{}
+	err = json.Unmarshal(encoded, &known)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]json.RawMessage, len(original))
+	for name, value := range original {
+		fields[name] = value
+	}
+	for name, value := range known {
+		originalValue, present := original[name]
+		if isZeroJSON(value) && (!present || string(originalValue) == "null") {
+			continue
+		}
+		fields[name] = value
+	}
+
+	return marshalJSON(fields)
+}
+
+func isZeroJSON(value json.RawMessage) bool {
+	switch string(value) {
+	case "null", `""`, "0", "false", "[]", "{}":
+		return true
+	}
+	return false
+}
+
+// javaPage is a page of results returned by the Java based KYPO services, such as the training services.
+type javaPage[T any] struct {
+	Content    T `json:"content"`
+	Pagination struct {
+		Number           int64 `json:"number"`
+		NumberOfElements int64 `json:"number_of_elements"`
+		Size             int64 `json:"size"`
+		TotalElements    int64 `json:"total_elements"`
+		TotalPages       int64 `json:"total_pages"`
+	} `json:"pagination"`
+}
+
+// toPagination converts the page to the Pagination format of the sandbox service, whose pages are numbered from 1.
+func (p *javaPage[T]) toPagination() Pagination[T] {
+	return Pagination[T]{
+		Page:       p.Pagination.Number + 1,
+		PageSize:   p.Pagination.Size,
+		PageCount:  p.Pagination.TotalPages,
+		Count:      p.Pagination.NumberOfElements,
+		TotalCount: p.Pagination.TotalElements,
+		Results:    p.Content,
+	}
+}
+
+// javaPageSize is the page size used when reading all pages from the Java based KYPO services.
+const javaPageSize = 100
+
+// getAllJavaPages reads the results of all pages from `pageURL` of a Java based KYPO service.
+func getAllJavaPages[T any](ctx context.Context, c *Client, pageURL string, resourceName string, identifier any) ([]T, error) {
+	separator := "?"
+	if strings.Contains(pageURL, "?") {
+		separator = "&"
+	}
+
+	var results []T
+	for page := 0; ; page++ {
+		var response javaPage[[]T]
+		err := c.doJSONRequest(ctx, http.MethodGet, fmt.Sprintf("%s%spage=%d&size=%d", pageURL, separator, page, javaPageSize),
+			nil, &response, http.StatusOK, resourceName, identifier)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, response.Content...)
+		if int64(page+1) >= response.Pagination.TotalPages {
+			return results, nil
+		}
+	}
+}
+
+// sandboxPageSize is the page size used when reading all pages from the sandbox service.
+const sandboxPageSize = 100
+
+// getAllSandboxPages reads the results of all pages from `pageURL` of the sandbox service, whose pages are numbered from 1.
+func getAllSandboxPages[T any](ctx context.Context, c *Client, pageURL string, resourceName string, identifier any) ([]T, error) {
+	separator := "?"
+	if strings.Contains(pageURL, "?") {
+		separator = "&"
+	}
+
+	var results []T
+	for page := 1; ; page++ {
+		var response Pagination[[]T]
+		err := c.doJSONRequest(ctx, http.MethodGet, fmt.Sprintf("%s%spage=%d&page_size=%d", pageURL, separator, page, sandboxPageSize),
+			nil, &response, http.StatusOK, resourceName, identifier)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, response.Results...)
+		if int64(page) >= response.PageCount {
+			return results, nil
+		}
+	}
+}
+
+// userRef is a reference to a user as returned by the training services.
+type userRef struct {
+	UserRefId  int64  `json:"user_ref_id"`
+	Sub        string `json:"sub"`
+	FullName   string `json:"full_name"`
+	GivenName  string `json:"given_name"`
+	FamilyName string `json:"family_name"`
+	Mail       string `json:"mail"`
+}
+
+func (u userRef) toUser() User {
+	return User{
+		Id:         u.UserRefId,
+		Sub:        u.Sub,
+		FullName:   u.FullName,
+		GivenName:  u.GivenName,
+		FamilyName: u.FamilyName,
+		Mail:       u.Mail,
+	}
+}